@@ -0,0 +1,82 @@
+package heap
+
+import (
+	"iter"
+	"math/bits"
+
+	c "golang.org/x/exp/constraints"
+)
+
+// PushAll adds the elements of a slice to the heap for a T that satisfies
+// constraints.Ordered. Unlike FromSlice, the existing contents of the heap are
+// kept, and the elements are copied, so the slice can still be used following
+// a call to this function. Depending on the number of elements added relative
+// to the size of the heap, either each new element is sifted up as for Push, or
+// the whole heap is rebuilt as for FromSlice.
+func PushAll[T c.Ordered, MOM MinOrMax](heap *Heap[T, MOM], elems []T) {
+	pushAll(heap, elems, func(i, j int) int { return cmpOrdered(heap.sl[i], heap.sl[j]) })
+}
+
+// As for PushAll, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func PushAllOrderable[T Orderable[T], MOM MinOrMax](heap *Heap[T, MOM], elems []T) {
+	pushAll(heap, elems, func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	})
+}
+
+// PushSeq adds the elements of a sequence to the heap for a T that satisfies
+// constraints.Ordered. It works as for PushAll.
+func PushSeq[T c.Ordered, MOM MinOrMax](heap *Heap[T, MOM], seq iter.Seq[T]) {
+	pushSeq(heap, seq, func(i, j int) int { return cmpOrdered(heap.sl[i], heap.sl[j]) })
+}
+
+// As for PushSeq, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func PushSeqOrderable[T Orderable[T], MOM MinOrMax](heap *Heap[T, MOM], seq iter.Seq[T]) {
+	pushSeq(heap, seq, func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	})
+}
+
+func pushAll[T any, MOM MinOrMax](heap *Heap[T, MOM], elems []T, cmp func(i, j int) int) {
+	start := len(heap.sl)
+	heap.sl = append(heap.sl, elems...)
+	heapifyTail(heap, start, cmp)
+}
+
+func pushSeq[T any, MOM MinOrMax](heap *Heap[T, MOM], seq iter.Seq[T], cmp func(i, j int) int) {
+	start := len(heap.sl)
+	for elem := range seq {
+		heap.sl = append(heap.sl, elem)
+	}
+	heapifyTail(heap, start, cmp)
+}
+
+// heapifyTail restores the heap property given that heap.sl[:start] is a valid
+// heap.
+func heapifyTail[T any, MOM MinOrMax](heap *Heap[T, MOM], start int, cmp func(i, j int) int) {
+	if start == len(heap.sl) {
+		return
+	}
+	if shouldRebuild(start, len(heap.sl)-start) {
+		fromSlice(heap, heap.sl, cmp)
+		return
+	}
+	for i := start; i < len(heap.sl); i++ {
+		bubble(heap, i, cmp)
+	}
+}
+
+// shouldRebuild reports whether it's likely to be faster to rebuild a heap of
+// n+k elements using Floyd's algorithm than to sift up each of the k elements
+// appended to a heap of n elements. A rebuild takes at most 2(n+k) comparisons.
+// Sifting up takes O(1) comparisons per element on average, but up to log2(n)
+// for pathological inputs, so we rebuild once the cost of a rebuild is a small
+// multiple of the worst case for sifting up. See BenchmarkPushAllCrossover.
+func shouldRebuild(n, k int) bool {
+	if n < k {
+		return true
+	}
+	return 4*(n+k) < k*bits.Len(uint(n))
+}
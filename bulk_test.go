@@ -0,0 +1,72 @@
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestPushAll(t *testing.T) {
+	src := rand.NewSource(123)
+	for _, n := range []int{0, 1, 10, 100, 1000} {
+		for _, k := range []int{0, 1, 10, 100, 1000, 5000} {
+			var heap Heap[int, Min]
+			var naive []int
+			for range n {
+				v := int(src.Int63() % 100)
+				Push(&heap, v)
+				naive = append(naive, v)
+			}
+			batch := make([]int, k)
+			for i := range batch {
+				batch[i] = int(src.Int63() % 100)
+			}
+			before := slices.Clone(batch)
+			PushAll(&heap, batch)
+			naive = append(naive, batch...)
+			if !slices.Equal(before, batch) {
+				t.Errorf("PushAll should not modify its argument")
+			}
+			if !checkMinHeapProperty(&heap, 0) {
+				t.Fatalf("Min heap property violated (n=%v, k=%v):\n%v\n", n, k, debugPrintHeap(&heap))
+			}
+			if !slicesHaveSameElems(naive, heap.sl) {
+				t.Fatalf("Elements not the same (n=%v, k=%v)\n", n, k)
+			}
+		}
+	}
+}
+
+func TestPushSeq(t *testing.T) {
+	var heap Heap[int, Max]
+	PushSeq(&heap, slices.Values([]int{1, 5, 2, 9}))
+	PushSeq(&heap, slices.Values([]int{-3, 17, 18, 19, 14}))
+	if !checkMaxHeapProperty(&heap, 0) {
+		t.Errorf("Max heap property violated:\n%v\n", debugPrintHeap(&heap))
+	}
+	var got []int
+	for v := range Drain(&heap) {
+		got = append(got, v)
+	}
+	if expected := []int{19, 18, 17, 14, 9, 5, 2, 1, -3}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v\n", expected, got)
+	}
+}
+
+func TestPushAllOrderable(t *testing.T) {
+	var heap Heap[myCustomType, Min]
+	PushOrderable(&heap, myCustomType{Key: 5})
+	PushAllOrderable(&heap, []myCustomType{{Key: 3}, {Key: 7}, {Key: 1}})
+	PushSeqOrderable(&heap, slices.Values([]myCustomType{{Key: 0}, {Key: 4}}))
+	var keys []int
+	for {
+		v, ok := PopOrderable(&heap)
+		if !ok {
+			break
+		}
+		keys = append(keys, v.Key)
+	}
+	if expected := []int{0, 1, 3, 4, 5, 7}; !slices.Equal(keys, expected) {
+		t.Errorf("Expected %v, got %v\n", expected, keys)
+	}
+}
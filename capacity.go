@@ -0,0 +1,80 @@
+package heap
+
+import (
+	"fmt"
+	"slices"
+)
+
+// A ShrinkPolicy determines when a Heap allocates a smaller backing slice as
+// elements are removed from it. Set the policy of a heap using
+// SetShrinkPolicy.
+type ShrinkPolicy uint8
+
+const (
+	// ShrinkHalf is the default policy. Once the heap's length falls to half its
+	// capacity, the elements are copied to a new slice with no spare capacity.
+	// This keeps memory usage low, but a heap whose length oscillates around
+	// the point at which it grows may reallocate on almost every Push and Pop.
+	ShrinkHalf ShrinkPolicy = iota
+	// ShrinkQuarter waits until the heap's length falls to a quarter of its
+	// capacity, then copies the elements to a new slice with capacity for twice
+	// as many elements. The heap must then double in size before it's
+	// reallocated again, so oscillating heaps are not reallocated repeatedly.
+	ShrinkQuarter
+	// ShrinkNever never allocates a smaller backing slice, even if the heap
+	// becomes empty. Use ShrinkToFit to release spare capacity.
+	ShrinkNever
+)
+
+func (p ShrinkPolicy) String() string {
+	switch p {
+	case ShrinkHalf:
+		return "ShrinkHalf"
+	case ShrinkQuarter:
+		return "ShrinkQuarter"
+	case ShrinkNever:
+		return "ShrinkNever"
+	}
+	return fmt.Sprintf("ShrinkPolicy(%d)", uint8(p))
+}
+
+// SetShrinkPolicy sets the policy that determines when the heap allocates a
+// smaller backing slice as elements are removed from it.
+func SetShrinkPolicy[T any, MOM MinOrMax](heap *Heap[T, MOM], policy ShrinkPolicy) {
+	heap.policy = policy
+}
+
+// Cap returns the number of elements that the heap can hold without
+// allocating a larger backing slice.
+func Cap[T any, MOM MinOrMax](heap *Heap[T, MOM]) int {
+	return cap(heap.sl)
+}
+
+// Grow increases the heap's capacity, if necessary, so that another n
+// elements can be pushed without allocating. If n is negative, Grow panics.
+// Note that under the default ShrinkHalf policy, the extra capacity may be
+// released by a subsequent call to Pop or Filter.
+func Grow[T any, MOM MinOrMax](heap *Heap[T, MOM], n int) {
+	heap.sl = slices.Grow(heap.sl, n)
+}
+
+// Reset empties the heap but, unlike Clear, keeps the backing slice so that it
+// can be reused by subsequent pushes.
+func Reset[T any, MOM MinOrMax](heap *Heap[T, MOM]) {
+	clear(heap.sl)
+	heap.sl = heap.sl[:0]
+}
+
+// ShrinkToFit allocates a backing slice with no spare capacity (or a nil
+// slice if the heap is empty) if the current one has spare capacity.
+func ShrinkToFit[T any, MOM MinOrMax](heap *Heap[T, MOM]) {
+	if len(heap.sl) == 0 {
+		heap.sl = nil
+		return
+	}
+	if cap(heap.sl) > len(heap.sl) {
+		sl := make([]T, len(heap.sl))
+		copy(sl, heap.sl)
+		heap.sl = sl
+	}
+}
@@ -0,0 +1,126 @@
+package heap
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGrowAndReset(t *testing.T) {
+	var heap Heap[int, Min]
+	Grow(&heap, 100)
+	if Cap(&heap) < 100 {
+		t.Fatalf("Expected capacity of at least 100, got %v\n", Cap(&heap))
+	}
+	c := Cap(&heap)
+	for i := range 100 {
+		Push(&heap, 100-i)
+	}
+	if Cap(&heap) != c {
+		t.Errorf("Expected no reallocation after Grow, capacity changed from %v to %v\n", c, Cap(&heap))
+	}
+	Reset(&heap)
+	if Len(&heap) != 0 || Cap(&heap) != c {
+		t.Errorf("Expected empty heap with capacity %v, got length %v and capacity %v\n", c, Len(&heap), Cap(&heap))
+	}
+	Push(&heap, 3)
+	Push(&heap, 1)
+	if v, ok := Peek(&heap); !ok || v != 1 {
+		t.Errorf("Expected (1,true), got (%v,%v)\n", v, ok)
+	}
+	Clear(&heap)
+	if heap.sl != nil {
+		t.Errorf("Expected Clear to release the backing slice")
+	}
+}
+
+func TestShrinkToFit(t *testing.T) {
+	var heap Heap[int, Max]
+	SetShrinkPolicy(&heap, ShrinkNever)
+	for i := range 100 {
+		Push(&heap, i)
+	}
+	for range 90 {
+		Pop(&heap)
+	}
+	if Cap(&heap) < 100 {
+		t.Errorf("Expected ShrinkNever to keep the backing slice, got capacity %v\n", Cap(&heap))
+	}
+	ShrinkToFit(&heap)
+	if Len(&heap) != 10 || Cap(&heap) != 10 || !checkMaxHeapProperty(&heap, 0) {
+		t.Errorf("Unexpected heap after ShrinkToFit (cap %v):\n%v\n", Cap(&heap), debugPrintHeap(&heap))
+	}
+	for range 10 {
+		Pop(&heap)
+	}
+	if heap.sl == nil || Cap(&heap) != 10 {
+		t.Errorf("Expected ShrinkNever to keep the backing slice of an empty heap")
+	}
+	ShrinkToFit(&heap)
+	if heap.sl != nil {
+		t.Errorf("Expected ShrinkToFit to release the backing slice of an empty heap")
+	}
+}
+
+func TestShrinkPolicies(t *testing.T) {
+	for _, tc := range []struct {
+		policy ShrinkPolicy
+		// expected capacity after popping down to 64 and to 32 elements
+		cap64, cap32 int
+	}{
+		{ShrinkHalf, 64, 32},
+		{ShrinkQuarter, 128, 64},
+		{ShrinkNever, 128, 128},
+	} {
+		var heap Heap[int, Min]
+		SetShrinkPolicy(&heap, tc.policy)
+		FromSlice(&heap, make([]int, 128))
+		for range 64 {
+			Pop(&heap)
+		}
+		if Cap(&heap) != tc.cap64 {
+			t.Errorf("Policy %v: expected capacity %v at length 64, got %v\n", tc.policy, tc.cap64, Cap(&heap))
+		}
+		for range 32 {
+			Pop(&heap)
+		}
+		if Cap(&heap) != tc.cap32 {
+			t.Errorf("Policy %v: expected capacity %v at length 32, got %v\n", tc.policy, tc.cap32, Cap(&heap))
+		}
+		for range 32 {
+			Pop(&heap)
+		}
+		if (heap.sl == nil) != (tc.policy != ShrinkNever) {
+			t.Errorf("Policy %v: unexpected backing slice for empty heap: %v\n", tc.policy, heap.sl)
+		}
+	}
+}
+
+func TestShrinkPolicyCopy(t *testing.T) {
+	var heap Heap[int, Min]
+	SetShrinkPolicy(&heap, ShrinkQuarter)
+	Push(&heap, 1)
+	cp := Copy(&heap)
+	if cp.policy != ShrinkQuarter {
+		t.Errorf("Expected Copy to preserve the shrink policy")
+	}
+}
+
+// Pushes and pops at a size where a push grows the backing slice and a pop
+// then shrinks it again under the default policy.
+func BenchmarkShrinkPolicyOscillation(b *testing.B) {
+	for _, n := range []int{64, 128, 256} {
+		for _, policy := range []ShrinkPolicy{ShrinkHalf, ShrinkQuarter, ShrinkNever} {
+			b.Run(fmt.Sprintf("n=%v/policy=%v", n, policy), func(b *testing.B) {
+				var heap Heap[int, Min]
+				SetShrinkPolicy(&heap, policy)
+				FromSlice(&heap, make([]int, n))
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := range b.N {
+					Push(&heap, i)
+					Pop(&heap)
+				}
+			})
+		}
+	}
+}
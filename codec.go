@@ -0,0 +1,138 @@
+package heap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+)
+
+// A Codec converts values of type T to and from bytes so that they can be
+// stored outside of memory (e.g. by the durable subpackage). Encoded values are
+// framed by the caller, so a Codec need not record the length of each value.
+type Codec[T any] interface {
+	// Append appends the encoding of v to dst and returns the extended buffer.
+	Append(dst []byte, v T) ([]byte, error)
+	// Decode decodes a value from data, which holds exactly one value encoded by
+	// Append. Decode must not retain data.
+	Decode(data []byte) (T, error)
+}
+
+// ErrCorruptStream is returned when a stream read by DecodeStream or
+// SortStream contains a malformed length prefix.
+var ErrCorruptStream = errors.New("heap: corrupt stream")
+
+const (
+	// maxFrameLen is the maximum length of an encoded value in a stream.
+	maxFrameLen = 1 << 30
+	// frameChunkLen is the amount by which readFramed grows its buffer at a
+	// time, so that a corrupt length prefix can't cause a huge allocation
+	// unless the reader actually supplies that much data.
+	frameChunkLen = 64 << 10
+)
+
+// JSONCodec is a Codec that encodes values as JSON using encoding/json. It
+// works for any T that can be round-tripped through encoding/json.
+type JSONCodec[T any] struct{}
+
+// Append implements Codec.
+func (JSONCodec[T]) Append(dst []byte, v T) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, b...), nil
+}
+
+// Decode implements Codec.
+func (JSONCodec[T]) Decode(data []byte) (v T, err error) {
+	err = json.Unmarshal(data, &v)
+	return
+}
+
+// EncodeStream writes the elements of seq to w, each encoded using codec and
+// preceded by the uvarint-encoded length of its encoding. This is the stream
+// format read by DecodeStream and SortStream.
+func EncodeStream[T any](w io.Writer, codec Codec[T], seq iter.Seq[T]) error {
+	bw := bufio.NewWriter(w)
+	var buf []byte
+	var err error
+	for v := range seq {
+		if buf, err = writeFramed(bw, codec, buf, v); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// DecodeStream returns an iterator over the elements of a stream written by
+// EncodeStream. If an error occurs then it is yielded with the zero value of T
+// and iteration stops.
+func DecodeStream[T any](r io.Reader, codec Codec[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		br := bufio.NewReader(r)
+		var buf []byte
+		for {
+			var v T
+			var err error
+			v, buf, err = readFramed(br, codec, buf)
+			if err == io.EOF {
+				return
+			}
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeFramed writes the encoding of v to w, preceded by its uvarint-encoded
+// length. buf is used as scratch space and returned for reuse.
+func writeFramed[T any](w *bufio.Writer, codec Codec[T], buf []byte, v T) ([]byte, error) {
+	buf, err := codec.Append(buf[:0], v)
+	if err != nil {
+		return buf, err
+	}
+	var lenBuf [binary.MaxVarintLen64]byte
+	w.Write(lenBuf[:binary.PutUvarint(lenBuf[:], uint64(len(buf)))])
+	_, err = w.Write(buf)
+	return buf, err
+}
+
+// readFramed reads a value written by writeFramed. It returns io.EOF if there
+// are no more values, io.ErrUnexpectedEOF if the last value is incomplete and
+// ErrCorruptStream if the length prefix is invalid. buf is used as scratch
+// space and returned for reuse.
+func readFramed[T any](r *bufio.Reader, codec Codec[T], buf []byte) (v T, _ []byte, err error) {
+	l, err := binary.ReadUvarint(r)
+	if err != nil {
+		if err != io.EOF && err != io.ErrUnexpectedEOF {
+			err = fmt.Errorf("%w: %w", ErrCorruptStream, err)
+		}
+		return v, buf, err
+	}
+	if l > maxFrameLen {
+		return v, buf, fmt.Errorf("%w: value length %v exceeds the maximum of %v", ErrCorruptStream, l, maxFrameLen)
+	}
+
+	buf = buf[:0]
+	for remaining := int(l); remaining > 0; {
+		n := min(remaining, frameChunkLen)
+		buf = slices.Grow(buf, n)
+		chunk := buf[len(buf) : len(buf)+n]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return v, buf, err
+		}
+		buf = buf[:len(buf)+n]
+		remaining -= n
+	}
+	v, err = codec.Decode(buf)
+	return v, buf, err
+}
@@ -0,0 +1,19 @@
+package heap
+
+import "testing"
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	var codec Codec[myCustomType] = JSONCodec[myCustomType]{}
+	buf := []byte("prefix")
+	buf, err := codec.Append(buf, myCustomType{Key: 1, Content: "foo"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if string(buf[:6]) != "prefix" {
+		t.Errorf("Expected Append to preserve the existing contents of dst")
+	}
+	v, err := codec.Decode(buf[6:])
+	if err != nil || v.Key != 1 || v.Content != "foo" {
+		t.Errorf("Unexpected decode result: %v %v\n", v, err)
+	}
+}
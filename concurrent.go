@@ -0,0 +1,137 @@
+package heap
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	c "golang.org/x/exp/constraints"
+)
+
+// ErrClosed is returned when pushing to or popping from a closed
+// ConcurrentHeap.
+var ErrClosed = errors.New("heap: closed")
+
+// ConcurrentHeap is a min or max heap that is safe for concurrent use by
+// multiple goroutines. It can be used as a priority job queue: Pop blocks until
+// an element is available, the context is cancelled, or the heap is closed.
+//
+// Unlike Heap, the default value of ConcurrentHeap is not usable. Use
+// NewConcurrent or NewConcurrentOrderable to create a ConcurrentHeap.
+type ConcurrentHeap[T any, MOM MinOrMax] struct {
+	mu      sync.Mutex
+	heap    Heap[T, MOM]
+	cmp     func(i, j int) int
+	closed  bool
+	waiters int
+	// wake is closed (and then replaced) to wake up all goroutines blocked in
+	// Pop.
+	wake chan struct{}
+}
+
+// NewConcurrent returns an empty ConcurrentHeap for a T that satisfies
+// constraints.Ordered.
+func NewConcurrent[T c.Ordered, MOM MinOrMax]() *ConcurrentHeap[T, MOM] {
+	q := &ConcurrentHeap[T, MOM]{wake: make(chan struct{})}
+	q.cmp = func(i, j int) int { return cmpOrdered(q.heap.sl[i], q.heap.sl[j]) }
+	return q
+}
+
+// NewConcurrentOrderable returns an empty ConcurrentHeap for a T that
+// implements Orderable.
+func NewConcurrentOrderable[T Orderable[T], MOM MinOrMax]() *ConcurrentHeap[T, MOM] {
+	q := &ConcurrentHeap[T, MOM]{wake: make(chan struct{})}
+	q.cmp = func(i, j int) int {
+		return q.heap.sl[i].Cmp(q.heap.sl[j])
+	}
+	return q
+}
+
+// Len returns the number of elements in the heap.
+func (q *ConcurrentHeap[T, MOM]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.heap.sl)
+}
+
+// Push adds an element to the heap, waking a goroutine blocked in Pop if there
+// is one. Returns ErrClosed if the heap has been closed.
+func (q *ConcurrentHeap[T, MOM]) Push(elem T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrClosed
+	}
+	push(&q.heap, elem, q.cmp)
+	if q.waiters > 0 {
+		close(q.wake)
+		q.wake = make(chan struct{})
+	}
+	return nil
+}
+
+// Pop removes the min/max element from the heap, blocking until an element is
+// available if the heap is empty. Returns ctx.Err() if the context is cancelled
+// before an element becomes available, and ErrClosed if the heap is closed and
+// empty. Elements pushed before Close can still be popped after it.
+func (q *ConcurrentHeap[T, MOM]) Pop(ctx context.Context) (val T, err error) {
+	q.mu.Lock()
+	for {
+		if len(q.heap.sl) > 0 {
+			val, _ = pop(&q.heap, q.cmp)
+			q.mu.Unlock()
+			return
+		}
+		if q.closed {
+			q.mu.Unlock()
+			err = ErrClosed
+			return
+		}
+
+		wake := q.wake
+		q.waiters++
+		q.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+
+		q.mu.Lock()
+		q.waiters--
+		if err != nil {
+			q.mu.Unlock()
+			return
+		}
+	}
+}
+
+// TryPop removes the min/max element from the heap without blocking. The
+// second return value is false if the heap is empty.
+func (q *ConcurrentHeap[T, MOM]) TryPop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return pop(&q.heap, q.cmp)
+}
+
+// Peek returns the min/max element from the heap without removing it.
+func (q *ConcurrentHeap[T, MOM]) Peek() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return Peek(&q.heap)
+}
+
+// Close closes the heap and wakes all goroutines blocked in Pop. Subsequent
+// calls to Push return ErrClosed. Calling Close more than once has no effect.
+func (q *ConcurrentHeap[T, MOM]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+	q.closed = true
+	close(q.wake)
+}
@@ -0,0 +1,170 @@
+package heap
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentPushAndTryPop(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14}
+	q := NewConcurrent[int, Max]()
+	for _, elem := range elems {
+		if err := q.Push(elem); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+	}
+	if q.Len() != len(elems) {
+		t.Errorf("Expected length %v, got %v\n", len(elems), q.Len())
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(elems)))
+	for _, e := range elems {
+		v, ok := q.TryPop()
+		if !ok || v != e {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", e, v, ok)
+		}
+	}
+	if _, ok := q.TryPop(); ok {
+		t.Errorf("Calling TryPop on an empty heap should have returned ok=false")
+	}
+}
+
+func TestConcurrentPopBlocksUntilPush(t *testing.T) {
+	q := NewConcurrentOrderable[myCustomType, Min]()
+	result := make(chan myCustomType)
+	go func() {
+		v, err := q.Pop(context.Background())
+		if err != nil {
+			t.Errorf("Unexpected error: %v\n", err)
+		}
+		result <- v
+	}()
+
+	select {
+	case v := <-result:
+		t.Fatalf("Pop returned %v before any element was pushed", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	q.Push(myCustomType{Key: 1, Content: "foo"})
+	if v := <-result; v.Content != "foo" {
+		t.Errorf("Unexpected pop result: %v\n", v)
+	}
+}
+
+func TestConcurrentPopContextCancelled(t *testing.T) {
+	q := NewConcurrent[int, Min]()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := q.Pop(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v\n", err)
+	}
+
+	// A cancelled waiter must not swallow a subsequent push.
+	q.Push(1)
+	if v, ok := q.TryPop(); !ok || v != 1 {
+		t.Errorf("Expected (1,true), got (%v,%v)\n", v, ok)
+	}
+}
+
+func TestConcurrentCloseWakesWaiters(t *testing.T) {
+	q := NewConcurrent[int, Min]()
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := q.Pop(context.Background())
+			errs <- err
+		}()
+	}
+	// Wait until every goroutine is blocked in Pop, so that Close has to wake
+	// them.
+	for {
+		q.mu.Lock()
+		waiters := q.waiters
+		q.mu.Unlock()
+		if waiters == 10 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	q.Close()
+	q.Close()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, got %v\n", err)
+		}
+	}
+	if err := q.Push(1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed from Push after Close, got %v\n", err)
+	}
+}
+
+func TestConcurrentPopAfterCloseDrains(t *testing.T) {
+	q := NewConcurrent[int, Min]()
+	q.Push(2)
+	q.Push(1)
+	q.Close()
+	for _, e := range []int{1, 2} {
+		v, err := q.Pop(context.Background())
+		if err != nil || v != e {
+			t.Errorf("Expected (%v,nil), got (%v,%v)\n", e, v, err)
+		}
+	}
+	if _, err := q.Pop(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v\n", err)
+	}
+}
+
+func TestConcurrentProducersAndConsumers(t *testing.T) {
+	const nProducers = 8
+	const nPerProducer = 1000
+	q := NewConcurrent[int, Min]()
+
+	var consumers sync.WaitGroup
+	counts := make([]int, nProducers*nPerProducer)
+	var mu sync.Mutex
+	for i := 0; i < 4; i++ {
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			for {
+				v, err := q.Pop(context.Background())
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				counts[v]++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var producers sync.WaitGroup
+	for p := 0; p < nProducers; p++ {
+		producers.Add(1)
+		go func() {
+			defer producers.Done()
+			for i := 0; i < nPerProducer; i++ {
+				q.Push(p*nPerProducer + i)
+			}
+		}()
+	}
+	producers.Wait()
+	q.Close()
+	consumers.Wait()
+
+	for v, n := range counts {
+		if n != 1 {
+			t.Fatalf("Element %v popped %v times\n", v, n)
+		}
+	}
+}
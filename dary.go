@@ -0,0 +1,262 @@
+package heap
+
+import (
+	"github.com/savsgio/gotils/nocopy"
+	c "golang.org/x/exp/constraints"
+)
+
+// DaryHeap is a min or max heap backed by a slice denoting an implicit d-ary
+// heap, where d is chosen by the third type parameter. Higher arities give
+// shallower trees, which make Push cheaper and improve cache locality at the
+// cost of more comparisons per level in Pop. The default value of DaryHeap is a
+// valid empty heap.
+//
+//	var timers heap.DaryHeap[int64, heap.Min, heap.Arity4]
+//	heap.PushDary(&timers, deadline)
+type DaryHeap[T any, MOM MinOrMax, A Arity] struct {
+	sl []T
+	nocopy.NoCopy
+}
+
+// The Arity interface has implementations (Arity2, Arity4 and Arity8) that can
+// be passed as type parameters to DaryHeap to choose the number of children of
+// each node.
+type Arity interface {
+	arity() int
+}
+
+// Pass this type as the third parameter of DaryHeap to specify a binary heap
+type Arity2 struct{}
+
+// Pass this type as the third parameter of DaryHeap to specify a 4-ary heap
+type Arity4 struct{}
+
+// Pass this type as the third parameter of DaryHeap to specify an 8-ary heap
+type Arity8 struct{}
+
+func (Arity2) arity() int {
+	return 2
+}
+
+func (Arity4) arity() int {
+	return 4
+}
+
+func (Arity8) arity() int {
+	return 8
+}
+
+// LenDary returns the number of elements in the heap.
+func LenDary[T any, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A]) int {
+	return len(heap.sl)
+}
+
+// PushDary adds an element to the heap for a T that satisfies
+// constraints.Ordered.
+func PushDary[T c.Ordered, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], elem T) {
+	pushDary(heap, elem, func(i, j int) int { return cmpOrdered(heap.sl[i], heap.sl[j]) })
+}
+
+// PushDaryOrderable adds an element to the heap for a T that implements
+// Orderable.
+func PushDaryOrderable[T Orderable[T], MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], elem T) {
+	pushDary(heap, elem, func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	})
+}
+
+func pushDary[T any, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], elem T, cmp func(i, j int) int) {
+	heap.sl = append(heap.sl, elem)
+	bubbleDary(heap, len(heap.sl)-1, cmp)
+}
+
+// PopDary removes the min/max element from the heap for a T that satisfies
+// constraints.Ordered.
+func PopDary[T c.Ordered, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A]) (T, bool) {
+	return popDary(heap, func(i, j int) int { return cmpOrdered(heap.sl[i], heap.sl[j]) })
+}
+
+// PopDaryOrderable removes the min/max element from the heap for a T that
+// implements Orderable.
+func PopDaryOrderable[T Orderable[T], MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A]) (T, bool) {
+	return popDary(heap, func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	})
+}
+
+func popDary[T any, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], cmp func(i, j int) int) (val T, ok bool) {
+	// See the comments in pop.
+
+	if len(heap.sl) == 0 {
+		return
+	}
+
+	ok = true
+	val = heap.sl[0]
+
+	i := pushRootHoleDownToLeafDary(heap, cmp)
+
+	if i+1 == len(heap.sl) {
+		heap.sl = shrink(heap.sl)
+		return
+	}
+
+	displaced := heap.sl[len(heap.sl)-1]
+	heap.sl = shrink(heap.sl)
+	heap.sl[i] = displaced
+	bubbleDary(heap, i, cmp)
+
+	return
+}
+
+// PeekDary returns the min/max element from the heap without removing it.
+func PeekDary[T any, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A]) (val T, ok bool) {
+	if len(heap.sl) == 0 {
+		return
+	}
+	ok = true
+	val = heap.sl[0]
+	return
+}
+
+// ClearDary empties the heap.
+func ClearDary[T any, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A]) {
+	heap.sl = nil
+}
+
+// FilterDary is the equivalent of Filter for a DaryHeap.
+func FilterDary[T c.Ordered, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], f func(*T) (keepElement bool, breakOrContinue BreakOrContinue)) {
+	filterDary(heap, f, func(i, j int) int { return cmpOrdered(heap.sl[i], heap.sl[j]) })
+}
+
+// As for FilterDary, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func FilterDaryOrderable[T Orderable[T], MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], f func(*T) (keepElement bool, breakOrContinue BreakOrContinue)) {
+	filterDary(heap, f, func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	})
+}
+
+func filterDary[T any, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], f func(*T) (bool, BreakOrContinue), cmp func(int, int) int) {
+	n := len(heap.sl)
+	i := 0
+	first := -1
+	for j := 0; j < len(heap.sl); j++ {
+		keep, boc := f(&heap.sl[j])
+		if keep {
+			heap.sl[i] = heap.sl[j]
+			if first == -1 {
+				first = i
+			}
+			i++
+		}
+		if boc == Break {
+			break
+		}
+	}
+
+	heap.sl = heap.sl[:i]
+
+	if first != -1 {
+		heap.sl = heap.sl[:first]
+		for j := first; j < i; j++ {
+			pushDary(heap, heap.sl[j : j+1][0], cmp)
+		}
+	}
+
+	// zero the vacated slots (as for shrink)
+	clear(heap.sl[i:n])
+	heap.sl = compact(heap.sl)
+}
+
+// FromSliceDary is the equivalent of FromSlice for a DaryHeap.
+func FromSliceDary[T c.Ordered, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], slice []T) {
+	fromSliceDary(heap, slice, func(i, j int) int {
+		return cmpOrdered(heap.sl[i], heap.sl[j])
+	})
+}
+
+// As for FromSliceDary, but for the case where T cannot be compared using <
+// and there is an implementation of Orderable[T].
+func FromSliceDaryOrderable[T Orderable[T], MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], slice []T) {
+	fromSliceDary(heap, slice, func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	})
+}
+
+func fromSliceDary[T any, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], slice []T, cmp func(int, int) int) {
+	var mom MOM
+	var a A
+	d := a.arity()
+
+	if len(slice) == 0 {
+		heap.sl = nil
+		return
+	}
+
+	heap.sl = slice
+	for i := daryParentIndex(len(heap.sl)-1, d); i >= 0; i-- {
+		j := i
+		for {
+			best := j
+			first := daryFirstChildIndex(j, d)
+			for ci := first; ci < first+d && ci < len(heap.sl); ci++ {
+				if mom.mul()*cmp(ci, best) < 0 {
+					best = ci
+				}
+			}
+			if best == j {
+				break
+			}
+			heap.sl[j], heap.sl[best] = heap.sl[best], heap.sl[j]
+			j = best
+		}
+	}
+}
+
+func pushRootHoleDownToLeafDary[T any, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], cmp func(i, j int) int) int {
+	var mom MOM
+	var a A
+	d := a.arity()
+
+	i := 0
+	for {
+		first := daryFirstChildIndex(i, d)
+		if first >= len(heap.sl) {
+			break
+		}
+
+		best := first
+		for ci := first + 1; ci < first+d && ci < len(heap.sl); ci++ {
+			if mom.mul()*cmp(ci, best) < 0 {
+				best = ci
+			}
+		}
+		heap.sl[i] = heap.sl[best]
+		i = best
+	}
+	return i
+}
+
+func bubbleDary[T any, MOM MinOrMax, A Arity](heap *DaryHeap[T, MOM, A], i int, cmp func(i, j int) int) {
+	var mom MOM
+	var a A
+	d := a.arity()
+
+	for i > 0 {
+		pi := daryParentIndex(i, d)
+		if mom.mul()*cmp(i, pi) >= 0 {
+			break
+		}
+		heap.sl[i], heap.sl[pi] = heap.sl[pi], heap.sl[i]
+		i = pi
+	}
+}
+
+func daryParentIndex(i, d int) int {
+	return (i - 1) / d
+}
+
+func daryFirstChildIndex(i, d int) int {
+	return (i * d) + 1
+}
@@ -0,0 +1,157 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestDaryPushAndPop(t *testing.T) {
+	testDaryPushAndPop[Arity2](t)
+	testDaryPushAndPop[Arity4](t)
+	testDaryPushAndPop[Arity8](t)
+}
+
+func testDaryPushAndPop[A Arity](t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14, 0, 3, 3, 7, 22, -8, 11}
+	var heap DaryHeap[int, Min, A]
+	for _, elem := range elems {
+		PushDary(&heap, elem)
+	}
+	if !checkDaryMinHeapProperty(&heap) {
+		t.Errorf("Min heap property violated")
+	}
+	sort.Ints(elems)
+	for i := 0; i < len(elems); i++ {
+		v, ok := PopDary(&heap)
+		if !ok || v != elems[i] {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", elems[i], v, ok)
+		}
+	}
+	if heap.sl != nil {
+		t.Errorf("Expecting empty heap to have nil backing slice")
+	}
+	if _, ok := PopDary(&heap); ok {
+		t.Errorf("Calling PopDary on an empty heap should have returned ok=false")
+	}
+}
+
+func TestDaryMax(t *testing.T) {
+	var heap DaryHeap[myCustomType, Max, Arity4]
+	for _, k := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		PushDaryOrderable(&heap, myCustomType{Key: k})
+	}
+	for _, k := range []int{9, 6, 5, 4, 3, 2, 1, 1} {
+		if v, ok := PeekDary(&heap); !ok || v.Key != k {
+			t.Errorf("Expected peek of %v, got (%v,%v)\n", k, v, ok)
+		}
+		if v, ok := PopDaryOrderable(&heap); !ok || v.Key != k {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", k, v, ok)
+		}
+	}
+}
+
+func TestDaryFilter(t *testing.T) {
+	var heap DaryHeap[int, Min, Arity4]
+	for i := 1; i <= 30; i++ {
+		PushDary(&heap, i)
+	}
+	FilterDary(&heap, func(elem *int) (bool, BreakOrContinue) {
+		return (*elem)%3 == 0, Continue
+	})
+	if !checkDaryMinHeapProperty(&heap) {
+		t.Errorf("Min heap property violated")
+	}
+	if LenDary(&heap) != 10 {
+		t.Errorf("Expected 10 elements, got %v\n", LenDary(&heap))
+	}
+	FilterDary(&heap, func(elem *int) (bool, BreakOrContinue) {
+		return false, Continue
+	})
+	if heap.sl != nil {
+		t.Errorf("Expected heap slice to be nil: %+v\n", heap.sl)
+	}
+}
+
+func TestDaryFromSliceFuzz(t *testing.T) {
+	testDaryFromSliceFuzz[Arity2](t)
+	testDaryFromSliceFuzz[Arity4](t)
+	testDaryFromSliceFuzz[Arity8](t)
+}
+
+func testDaryFromSliceFuzz[A Arity](t *testing.T) {
+	src := rand.NewSource(123)
+	slice := make([]int, 10000)
+	for i := 0; i < len(slice); i++ {
+		slice[i] = int(src.Int63() % 1000)
+	}
+	sliceCp := make([]int, len(slice))
+	copy(sliceCp, slice)
+	sort.Ints(sliceCp)
+	var heap DaryHeap[int, Min, A]
+	FromSliceDary(&heap, slice)
+	if !checkDaryMinHeapProperty(&heap) {
+		t.Fatalf("Min heap property violated")
+	}
+	for i := 0; i < len(sliceCp); i++ {
+		v, ok := PopDary(&heap)
+		if !ok || v != sliceCp[i] {
+			t.Fatalf("Expected (%v,true), got (%v,%v)\n", sliceCp[i], v, ok)
+		}
+	}
+}
+
+// Fuzz tests a randomly generated sequence of operations against the same set
+// of operations performed on a sorted slice.
+func TestDaryHeapFuzz(t *testing.T) {
+	testDaryHeapFuzz[Arity2](t)
+	testDaryHeapFuzz[Arity4](t)
+	testDaryHeapFuzz[Arity8](t)
+}
+
+func testDaryHeapFuzz[A Arity](t *testing.T) {
+	src := rand.NewSource(123)
+
+	var realHeap DaryHeap[int, Min, A]
+	var naiveHeap []int
+
+	for i := 0; i < 10000; i++ {
+		rnd := src.Int63()
+		if rnd%13 == 0 {
+			naiveHeapPop(&naiveHeap)
+			PopDary(&realHeap)
+		} else if rnd%17 == 0 {
+			f := func(v *int) (bool, BreakOrContinue) {
+				return (*v)%7 == 0, Continue
+			}
+			naiveHeapFilter(&naiveHeap, f)
+			FilterDary(&realHeap, f)
+		} else {
+			v := int(rnd % 100)
+			naiveMinHeapPush(&naiveHeap, v)
+			PushDary(&realHeap, v)
+		}
+
+		if !checkDaryMinHeapProperty(&realHeap) {
+			t.Fatalf("Real heap does not have min heap property")
+		}
+
+		if !slicesHaveSameElems(naiveHeap, realHeap.sl) {
+			t.Fatalf("Elements not the same:\n%+v\n\n%+v\n", naiveHeap, realHeap.sl)
+		}
+	}
+
+	for {
+		v1, ok1 := PopDary(&realHeap)
+		v2, ok2 := naiveHeapPop(&naiveHeap)
+
+		if v1 != v2 || ok1 != ok2 {
+			t.Errorf("Oh no! Got %v,%v, expected %v,%v.\n", v1, ok1, v2, ok2)
+			break
+		}
+
+		if !ok1 {
+			break
+		}
+	}
+}
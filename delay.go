@@ -0,0 +1,204 @@
+package heap
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A Clock tells the time and creates timers. DelayQueue uses a Clock so that
+// tests can control the passage of time. SystemClock is the real clock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer returns a Timer that sends the current time on its channel
+	// after at least d has elapsed.
+	NewTimer(d time.Duration) Timer
+}
+
+// A Timer is a single event created by Clock.NewTimer.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing. It returns false if the timer has
+	// already fired or been stopped.
+	Stop() bool
+}
+
+// SystemClock is the Clock that uses the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer { return systemTimer{time.NewTimer(d)} }
+
+type systemTimer struct{ t *time.Timer }
+
+func (t systemTimer) C() <-chan time.Time { return t.t.C }
+
+func (t systemTimer) Stop() bool { return t.t.Stop() }
+
+// DelayQueue is a queue of elements that each become available at a given
+// time. Elements are taken in order of their ready times; elements with the
+// same ready time are taken in the order in which they were pushed. It is safe
+// for concurrent use by multiple goroutines.
+//
+// Unlike Heap, the default value of DelayQueue is not usable. Use NewDelayQueue
+// to create a DelayQueue.
+type DelayQueue[T any] struct {
+	mu      sync.Mutex
+	clock   Clock
+	heap    Heap[delayEntry[T], Min]
+	seq     uint64
+	closed  bool
+	waiters int
+	// wake is closed (and then replaced) to wake up all goroutines blocked in
+	// Take.
+	wake chan struct{}
+}
+
+type delayEntry[T any] struct {
+	val     T
+	readyAt time.Time
+	seq     uint64
+}
+
+// NewDelayQueue returns an empty DelayQueue that uses the given clock. If clock
+// is nil then SystemClock is used.
+func NewDelayQueue[T any](clock Clock) *DelayQueue[T] {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &DelayQueue[T]{clock: clock, wake: make(chan struct{})}
+}
+
+func (q *DelayQueue[T]) cmp(i, j int) int {
+	a, b := &q.heap.sl[i], &q.heap.sl[j]
+	if r := a.readyAt.Compare(b.readyAt); r != 0 {
+		return r
+	}
+	return cmpOrdered(a.seq, b.seq)
+}
+
+// Len returns the number of elements in the queue, whether or not they are
+// ready.
+func (q *DelayQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.heap.sl)
+}
+
+// Push adds an element to the queue that becomes available at readyAt. If the
+// element becomes the head of the queue then goroutines blocked in Take are
+// woken so that they can wait for the new ready time instead. Returns ErrClosed
+// if the queue has been closed.
+func (q *DelayQueue[T]) Push(elem T, readyAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrClosed
+	}
+	seq := q.seq
+	q.seq++
+	push(&q.heap, delayEntry[T]{elem, readyAt, seq}, q.cmp)
+	if q.waiters > 0 && q.heap.sl[0].seq == seq {
+		close(q.wake)
+		q.wake = make(chan struct{})
+	}
+	return nil
+}
+
+// Take removes the element with the earliest ready time from the queue,
+// blocking until it is ready. Returns ctx.Err() if the context is cancelled
+// before an element is ready, and ErrClosed if the queue is closed and empty.
+// Elements pushed before Close can still be taken after it.
+func (q *DelayQueue[T]) Take(ctx context.Context) (val T, err error) {
+	q.mu.Lock()
+	for {
+		var timer Timer
+		var timerC <-chan time.Time
+		if len(q.heap.sl) > 0 {
+			d := q.heap.sl[0].readyAt.Sub(q.clock.Now())
+			if d <= 0 {
+				e, _ := pop(&q.heap, q.cmp)
+				q.mu.Unlock()
+				return e.val, nil
+			}
+			timer = q.clock.NewTimer(d)
+			timerC = timer.C()
+		} else if q.closed {
+			q.mu.Unlock()
+			err = ErrClosed
+			return
+		}
+
+		// Once closed, wake stays closed, so only wait for the timer.
+		var wake <-chan struct{}
+		if !q.closed {
+			wake = q.wake
+		}
+		q.waiters++
+		q.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-timerC:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+
+		q.mu.Lock()
+		q.waiters--
+		if err != nil {
+			q.mu.Unlock()
+			return
+		}
+	}
+}
+
+// TryTake removes the element with the earliest ready time from the queue if
+// it is ready, without blocking. The second return value is false if no
+// element is ready.
+func (q *DelayQueue[T]) TryTake() (val T, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.heap.sl) == 0 || q.heap.sl[0].readyAt.After(q.clock.Now()) {
+		return
+	}
+	e, _ := pop(&q.heap, q.cmp)
+	return e.val, true
+}
+
+// Peek returns the element with the earliest ready time and its ready time
+// without removing it, whether or not it is ready. The last return value is
+// false if the queue is empty.
+func (q *DelayQueue[T]) Peek() (val T, readyAt time.Time, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.heap.sl) == 0 {
+		return
+	}
+	e := q.heap.sl[0]
+	return e.val, e.readyAt, true
+}
+
+// Close closes the queue and wakes all goroutines blocked in Take. Subsequent
+// calls to Push return ErrClosed. Calling Close more than once has no effect.
+func (q *DelayQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+	q.closed = true
+	close(q.wake)
+}
@@ -0,0 +1,220 @@
+package heap
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only changes when Advance is called.
+type fakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers map[*fakeTimer]struct{}
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	when  time.Time
+	c     chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	fc := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), timers: map[*fakeTimer]struct{}{}}
+	fc.cond = sync.NewCond(&fc.mu)
+	return fc
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+func (fc *fakeClock) NewTimer(d time.Duration) Timer {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	t := &fakeTimer{clock: fc, when: fc.now.Add(d), c: make(chan time.Time, 1)}
+	fc.timers[t] = struct{}{}
+	fc.cond.Broadcast()
+	return t
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	_, ok := t.clock.timers[t]
+	delete(t.clock.timers, t)
+	t.clock.cond.Broadcast()
+	return ok
+}
+
+// Advance moves the clock forward by d, firing any timers that become due.
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.now = fc.now.Add(d)
+	for t := range fc.timers {
+		if !t.when.After(fc.now) {
+			delete(fc.timers, t)
+			t.c <- fc.now
+		}
+	}
+	fc.cond.Broadcast()
+}
+
+// waitForTimer blocks until there is a pending timer that fires at when.
+func (fc *fakeClock) waitForTimer(when time.Time) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	for {
+		for t := range fc.timers {
+			if t.when.Equal(when) {
+				return
+			}
+		}
+		fc.cond.Wait()
+	}
+}
+
+func TestDelayQueueTryTake(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue[string](clock)
+	start := clock.Now()
+	q.Push("c", start.Add(3*time.Second))
+	q.Push("a", start.Add(time.Second))
+	q.Push("b", start.Add(time.Second))
+	if q.Len() != 3 {
+		t.Errorf("Expected length 3, got %v\n", q.Len())
+	}
+	if v, readyAt, ok := q.Peek(); !ok || v != "a" || !readyAt.Equal(start.Add(time.Second)) {
+		t.Errorf("Unexpected Peek result: (%v,%v,%v)\n", v, readyAt, ok)
+	}
+	if v, ok := q.TryTake(); ok {
+		t.Errorf("Expected no element to be ready, got %v\n", v)
+	}
+
+	clock.Advance(time.Second)
+	for _, expected := range []string{"a", "b"} {
+		if v, ok := q.TryTake(); !ok || v != expected {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", expected, v, ok)
+		}
+	}
+	if v, ok := q.TryTake(); ok {
+		t.Errorf("Expected no element to be ready, got %v\n", v)
+	}
+	clock.Advance(5 * time.Second)
+	if v, ok := q.TryTake(); !ok || v != "c" {
+		t.Errorf("Expected (c,true), got (%v,%v)\n", v, ok)
+	}
+}
+
+func TestDelayQueueTakeWaitsForReadyTime(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue[int](clock)
+	readyAt := clock.Now().Add(time.Minute)
+	q.Push(1, readyAt)
+
+	result := make(chan int)
+	go func() {
+		v, err := q.Take(context.Background())
+		if err != nil {
+			t.Errorf("Unexpected error: %v\n", err)
+		}
+		result <- v
+	}()
+
+	clock.waitForTimer(readyAt)
+	clock.Advance(59 * time.Second)
+	select {
+	case v := <-result:
+		t.Fatalf("Take returned %v before the element was ready", v)
+	default:
+	}
+	clock.Advance(time.Second)
+	if v := <-result; v != 1 {
+		t.Errorf("Expected 1, got %v\n", v)
+	}
+}
+
+func TestDelayQueueTakeWakesForNewHead(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue[string](clock)
+	start := clock.Now()
+	q.Push("late", start.Add(time.Hour))
+
+	result := make(chan string)
+	go func() {
+		v, _ := q.Take(context.Background())
+		result <- v
+	}()
+
+	clock.waitForTimer(start.Add(time.Hour))
+	q.Push("early", start.Add(time.Second))
+	clock.waitForTimer(start.Add(time.Second))
+	clock.Advance(time.Second)
+	if v := <-result; v != "early" {
+		t.Errorf("Expected early, got %v\n", v)
+	}
+	if q.Len() != 1 {
+		t.Errorf("Expected length 1, got %v\n", q.Len())
+	}
+}
+
+func TestDelayQueueTakeContextCancelled(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue[int](clock)
+	q.Push(1, clock.Now().Add(time.Hour))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := q.Take(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v\n", err)
+	}
+	if q.Len() != 1 {
+		t.Errorf("Expected element to remain in the queue")
+	}
+}
+
+func TestDelayQueueClose(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue[int](clock)
+	readyAt := clock.Now().Add(time.Second)
+	q.Push(1, readyAt)
+	q.Close()
+	q.Close()
+	if err := q.Push(2, readyAt); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v\n", err)
+	}
+
+	result := make(chan error)
+	go func() {
+		v, err := q.Take(context.Background())
+		if err == nil && v != 1 {
+			t.Errorf("Expected 1, got %v\n", v)
+		}
+		result <- err
+	}()
+	clock.waitForTimer(readyAt)
+	clock.Advance(time.Second)
+	if err := <-result; err != nil {
+		t.Errorf("Unexpected error: %v\n", err)
+	}
+	if _, err := q.Take(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v\n", err)
+	}
+}
+
+func TestDelayQueueSystemClock(t *testing.T) {
+	q := NewDelayQueue[int](nil)
+	q.Push(1, time.Now().Add(5*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if v, err := q.Take(ctx); err != nil || v != 1 {
+		t.Errorf("Expected (1,nil), got (%v,%v)\n", v, err)
+	}
+}
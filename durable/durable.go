@@ -0,0 +1,333 @@
+// Package durable provides a crash-safe priority queue backed by a heap.Heap
+// and a write-ahead log in a local directory.
+//
+// Every Push and Pop is appended to the log before it is applied to the
+// in-memory heap. When the queue is opened, the most recent snapshot is loaded
+// and the log is replayed on top of it. Once the log grows large relative to
+// the number of elements in the queue, it is compacted by writing a new
+// snapshot and starting a new log.
+//
+// Replay relies on the heap operations being deterministic, so a directory must
+// always be opened with the same element type, ordering and Cmp
+// implementation. A directory must not be opened by more than one Queue at a
+// time.
+package durable
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Mishka-Squat/heap"
+	c "golang.org/x/exp/constraints"
+)
+
+// ErrCorrupt is returned (wrapped) by Open if the snapshot or log in the
+// directory is corrupt. A torn record at the end of the log (as left by a
+// crash during a write) is not considered corruption; it is discarded.
+var ErrCorrupt = errors.New("durable: corrupt queue data")
+
+// ErrClosed is returned when using a Queue after it has been closed.
+var ErrClosed = errors.New("durable: queue closed")
+
+// Options configure a Queue. The zero value gives the default options.
+type Options struct {
+	// SyncEvery is the number of log records written between calls to fsync.
+	// The default (0 or 1) syncs after every Push and Pop. Larger values give
+	// better throughput, but up to SyncEvery-1 of the most recent operations may
+	// be lost if the machine crashes (though not if only the process crashes).
+	// Call Sync to force buffered records to stable storage.
+	SyncEvery int
+	// CompactAfter is the minimum number of log records before the log is
+	// compacted. Compaction also waits until the log has more than twice as
+	// many records as there are elements in the queue. Defaults to 1024. A
+	// negative value disables automatic compaction.
+	//
+	// If automatic compaction fails, the Push or Pop that triggered it still
+	// succeeds, as it has already been logged. Compaction is retried once
+	// another CompactAfter records have been written, and the error is returned
+	// by Close unless a later compaction succeeds.
+	CompactAfter int
+}
+
+const defaultCompactAfter = 1024
+
+// Queue is a durable min or max priority queue. It is safe for concurrent use
+// by multiple goroutines.
+type Queue[T any, MOM heap.MinOrMax] struct {
+	mu    sync.Mutex
+	dir   string
+	codec heap.Codec[T]
+	opts  Options
+
+	heap     heap.Heap[T, MOM]
+	push     func(*heap.Heap[T, MOM], T)
+	pop      func(*heap.Heap[T, MOM]) (T, bool)
+	fromSl   func(*heap.Heap[T, MOM], []T)
+	log      *logWriter
+	gen      uint64
+	unsynced int
+	closed   bool
+	// err is set if writing to the log fails, after which the state of the log
+	// is unknown and the queue can no longer be modified.
+	err error
+	// compactErr is the error from the last automatic compaction, if it failed.
+	// Automatic compaction isn't retried until the log has retryCompactAt
+	// records.
+	compactErr     error
+	retryCompactAt int
+	buf            []byte
+}
+
+// Open opens (creating if necessary) the durable queue stored in dir for a T
+// that satisfies constraints.Ordered. Elements are converted to bytes using
+// codec.
+func Open[T c.Ordered, MOM heap.MinOrMax](dir string, codec heap.Codec[T], opts Options) (*Queue[T, MOM], error) {
+	q := &Queue[T, MOM]{
+		push:   heap.Push[T, MOM],
+		pop:    heap.Pop[T, MOM],
+		fromSl: heap.FromSlice[T, MOM],
+	}
+	if err := q.open(dir, codec, opts); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// OpenOrderable is the equivalent of Open for a T that implements
+// heap.Orderable.
+func OpenOrderable[T heap.Orderable[T], MOM heap.MinOrMax](dir string, codec heap.Codec[T], opts Options) (*Queue[T, MOM], error) {
+	q := &Queue[T, MOM]{
+		push:   heap.PushOrderable[T, MOM],
+		pop:    heap.PopOrderable[T, MOM],
+		fromSl: heap.FromSliceOrderable[T, MOM],
+	}
+	if err := q.open(dir, codec, opts); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *Queue[T, MOM]) open(dir string, codec heap.Codec[T], opts Options) error {
+	q.dir = dir
+	q.codec = codec
+	q.opts = opts
+	if q.opts.SyncEvery < 1 {
+		q.opts.SyncEvery = 1
+	}
+	if q.opts.CompactAfter == 0 {
+		q.opts.CompactAfter = defaultCompactAfter
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	gen, elems, err := readSnapshot(filepath.Join(dir, snapshotName), codec)
+	if err != nil {
+		return err
+	}
+	q.gen = gen
+	q.fromSl(&q.heap, elems)
+
+	records, err := replayLog(filepath.Join(dir, logName(gen)), func(op byte, payload []byte) error {
+		switch op {
+		case opPush:
+			v, err := codec.Decode(payload)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrCorrupt, err)
+			}
+			q.push(&q.heap, v)
+		case opPop:
+			if _, ok := q.pop(&q.heap); !ok {
+				return fmt.Errorf("%w: pop from empty queue", ErrCorrupt)
+			}
+		default:
+			return fmt.Errorf("%w: unknown log record type %v", ErrCorrupt, op)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := removeStaleLogs(dir, gen); err != nil {
+		return err
+	}
+
+	q.log, err = openLogWriter(filepath.Join(dir, logName(gen)), records)
+	return err
+}
+
+// Len returns the number of elements in the queue.
+func (q *Queue[T, MOM]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return heap.Len(&q.heap)
+}
+
+// Peek returns the min/max element of the queue without removing it.
+func (q *Queue[T, MOM]) Peek() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return heap.Peek(&q.heap)
+}
+
+// Push adds an element to the queue. The element is logged before Push returns
+// (and synced, depending on Options.SyncEvery).
+func (q *Queue[T, MOM]) Push(elem T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrClosed
+	}
+	if q.err != nil {
+		return q.err
+	}
+
+	var err error
+	q.buf, err = q.codec.Append(q.buf[:0], elem)
+	if err != nil {
+		return err
+	}
+	if err := q.appendRecord(opPush, q.buf); err != nil {
+		return err
+	}
+	q.push(&q.heap, elem)
+	q.maybeCompact()
+	return nil
+}
+
+// Pop removes the min/max element from the queue. The removal is logged before
+// Pop returns (and synced, depending on Options.SyncEvery). The second return
+// value is false if the queue is empty.
+func (q *Queue[T, MOM]) Pop() (val T, ok bool, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		err = ErrClosed
+		return
+	}
+	if q.err != nil {
+		err = q.err
+		return
+	}
+	if heap.Len(&q.heap) == 0 {
+		return
+	}
+
+	if err = q.appendRecord(opPop, nil); err != nil {
+		return
+	}
+	val, ok = q.pop(&q.heap)
+	q.maybeCompact()
+	return
+}
+
+// Sync forces any log records that have not yet been synced to stable
+// storage.
+func (q *Queue[T, MOM]) Sync() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrClosed
+	}
+	return q.sync()
+}
+
+// Compact writes a snapshot of the queue and starts a new, empty log.
+func (q *Queue[T, MOM]) Compact() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrClosed
+	}
+	if q.err != nil {
+		return q.err
+	}
+	return q.compact()
+}
+
+// Close syncs the log and closes the queue. If the last automatic compaction
+// failed then its error is also returned.
+func (q *Queue[T, MOM]) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+	err := q.sync()
+	if cerr := q.log.close(); err == nil {
+		err = cerr
+	}
+	return errors.Join(err, q.compactErr)
+}
+
+func (q *Queue[T, MOM]) appendRecord(op byte, payload []byte) error {
+	if err := q.log.append(op, payload); err != nil {
+		q.err = err
+		return err
+	}
+	q.unsynced++
+	if q.unsynced >= q.opts.SyncEvery {
+		if err := q.sync(); err != nil {
+			q.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *Queue[T, MOM]) sync() error {
+	if q.unsynced == 0 {
+		return nil
+	}
+	if err := q.log.sync(); err != nil {
+		return err
+	}
+	q.unsynced = 0
+	return nil
+}
+
+func (q *Queue[T, MOM]) maybeCompact() {
+	if q.opts.CompactAfter < 0 || q.log.records < q.opts.CompactAfter || q.log.records < q.retryCompactAt || q.log.records <= 2*heap.Len(&q.heap) {
+		return
+	}
+	if err := q.compact(); err != nil {
+		q.compactErr = err
+		q.retryCompactAt = q.log.records + q.opts.CompactAfter
+	}
+}
+
+func (q *Queue[T, MOM]) compact() error {
+	// The new snapshot records the generation of the log that follows it. Once
+	// the snapshot has been renamed into place, the old log is no longer needed,
+	// so a crash at any point leaves a consistent snapshot/log pair.
+	gen := q.gen + 1
+	err := writeSnapshot(q.dir, gen, heap.Len(&q.heap), heap.All(&q.heap), q.codec)
+	if err != nil {
+		return err
+	}
+
+	newLog, err := openLogWriter(filepath.Join(q.dir, logName(gen)), 0)
+	if err != nil {
+		// The snapshot has already superseded the current log.
+		q.err = err
+		return err
+	}
+	q.log.close()
+	q.log = newLog
+	q.gen = gen
+	q.unsynced = 0
+	q.compactErr = nil
+	q.retryCompactAt = 0
+	return removeStaleLogs(q.dir, gen)
+}
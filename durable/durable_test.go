@@ -0,0 +1,336 @@
+package durable
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Mishka-Squat/heap"
+)
+
+type task struct {
+	Priority int
+	Name     string
+}
+
+func (a task) Cmp(b task) int {
+	return a.Priority - b.Priority
+}
+
+func popAll[T any, MOM heap.MinOrMax](t *testing.T, q *Queue[T, MOM]) []T {
+	var res []T
+	for {
+		v, ok, err := q.Pop()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if !ok {
+			return res
+		}
+		res = append(res, v)
+	}
+}
+
+func TestPushPopReopen(t *testing.T) {
+	dir := t.TempDir()
+	q, err := Open[int, heap.Min](dir, heap.JSONCodec[int]{}, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	for _, v := range []int{5, 3, 8, 1, 9, 2} {
+		if err := q.Push(v); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+	}
+	if v, ok, err := q.Pop(); v != 1 || !ok || err != nil {
+		t.Errorf("Expected (1,true,nil), got (%v,%v,%v)\n", v, ok, err)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if err := q.Push(1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v\n", err)
+	}
+
+	q, err = Open[int, heap.Min](dir, heap.JSONCodec[int]{}, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	defer q.Close()
+	if q.Len() != 5 {
+		t.Errorf("Expected 5 elements after reopening, got %v\n", q.Len())
+	}
+	if res := popAll(t, q); !slices.Equal(res, []int{2, 3, 5, 8, 9}) {
+		t.Errorf("Unexpected elements after reopening: %v\n", res)
+	}
+}
+
+func TestCompaction(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{CompactAfter: 16, SyncEvery: 8}
+	q, err := OpenOrderable[task, heap.Max](dir, heap.JSONCodec[task]{}, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	for i := 0; i < 100; i++ {
+		q.Push(task{i, "t"})
+		if i%3 == 0 {
+			q.Pop()
+		}
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	logs, _ := filepath.Glob(filepath.Join(dir, logPrefix+"*"))
+	if len(logs) != 1 {
+		t.Errorf("Expected exactly one log after compaction, got %v\n", logs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, snapshotName)); err != nil {
+		t.Errorf("Expected snapshot to exist: %v\n", err)
+	}
+
+	// the expected contents, computed with an ordinary heap
+	var expected heap.Heap[task, heap.Max]
+	for i := 0; i < 100; i++ {
+		heap.PushOrderable(&expected, task{i, "t"})
+		if i%3 == 0 {
+			heap.PopOrderable(&expected)
+		}
+	}
+
+	q, err = OpenOrderable[task, heap.Max](dir, heap.JSONCodec[task]{}, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	defer q.Close()
+	if res := popAll(t, q); !slices.Equal(res, slices.Collect(heap.DrainOrderable(&expected))) {
+		t.Errorf("Unexpected elements after reopening: %v\n", res)
+	}
+}
+
+var errSnapshotCodec = errors.New("snapshot codec failure")
+
+// failingCodec is a JSONCodec[int] whose Append fails while fail is set, and
+// which counts the calls to Append.
+type failingCodec struct {
+	fail    bool
+	appends int
+}
+
+func (fc *failingCodec) Append(dst []byte, v int) ([]byte, error) {
+	fc.appends++
+	if fc.fail {
+		return dst, errSnapshotCodec
+	}
+	return heap.JSONCodec[int]{}.Append(dst, v)
+}
+
+func (fc *failingCodec) Decode(data []byte) (int, error) {
+	return heap.JSONCodec[int]{}.Decode(data)
+}
+
+func TestCompactionFailure(t *testing.T) {
+	dir := t.TempDir()
+	codec := &failingCodec{}
+	q, err := Open[int, heap.Min](dir, codec, Options{CompactAfter: 4})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	for i := range 6 {
+		q.Push(i)
+	}
+
+	// Pops don't encode anything, so only the snapshot written by compaction
+	// fails. The pop that triggers it has still been committed.
+	codec.fail = true
+	codec.appends = 0
+	for i := range 4 {
+		if v, ok, err := q.Pop(); err != nil || !ok || v != i {
+			t.Fatalf("Expected (%v,true,nil), got (%v,%v,%v)\n", i, v, ok, err)
+		}
+	}
+	if codec.appends != 1 {
+		t.Errorf("Expected one failed compaction attempt, got %v calls to Append\n", codec.appends)
+	}
+
+	if err := q.Close(); !errors.Is(err, errSnapshotCodec) {
+		t.Errorf("Expected Close to return the compaction error, got %v\n", err)
+	}
+
+	codec.fail = false
+	q, err = Open[int, heap.Min](dir, codec, Options{CompactAfter: 4})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if res := popAll(t, q); !slices.Equal(res, []int{4, 5}) {
+		t.Errorf("Unexpected elements after reopening: %v\n", res)
+	}
+	if err := q.Close(); err != nil {
+		t.Errorf("Unexpected error: %v\n", err)
+	}
+}
+
+func TestExplicitCompactThenReopen(t *testing.T) {
+	dir := t.TempDir()
+	q, _ := Open[string, heap.Min](dir, heap.JSONCodec[string]{}, Options{CompactAfter: -1})
+	q.Push("b")
+	q.Push("a")
+	if err := q.Compact(); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	q.Push("c")
+	q.Close()
+
+	q, err := Open[string, heap.Min](dir, heap.JSONCodec[string]{}, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	defer q.Close()
+	if res := popAll(t, q); !slices.Equal(res, []string{"a", "b", "c"}) {
+		t.Errorf("Unexpected elements after reopening: %v\n", res)
+	}
+}
+
+func TestTornLogRecord(t *testing.T) {
+	dir := t.TempDir()
+	q, _ := Open[int, heap.Min](dir, heap.JSONCodec[int]{}, Options{})
+	q.Push(2)
+	q.Push(1)
+	q.Close()
+
+	// simulate a crash part way through appending a record
+	f, err := os.OpenFile(filepath.Join(dir, logName(0)), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	f.Write([]byte{opPush, 10, '1', '2'})
+	f.Close()
+
+	q, err = Open[int, heap.Min](dir, heap.JSONCodec[int]{}, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	q.Push(3)
+	q.Close()
+
+	q, err = Open[int, heap.Min](dir, heap.JSONCodec[int]{}, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	defer q.Close()
+	if res := popAll(t, q); !slices.Equal(res, []int{1, 2, 3}) {
+		t.Errorf("Unexpected elements after reopening: %v\n", res)
+	}
+}
+
+func TestCorruptLogRecord(t *testing.T) {
+	dir := t.TempDir()
+	q, _ := Open[int, heap.Min](dir, heap.JSONCodec[int]{}, Options{})
+	for i := 1; i <= 5; i++ {
+		q.Push(i)
+	}
+	q.Close()
+
+	// flip a byte of the first record's payload
+	path := filepath.Join(dir, logName(0))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	data[2] ^= 0xff
+	os.WriteFile(path, data, 0o644)
+
+	if _, err := Open[int, heap.Min](dir, heap.JSONCodec[int]{}, Options{}); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt, got %v\n", err)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Size() != int64(len(data)) {
+		t.Errorf("Expected the log to be left unchanged")
+	}
+}
+
+func TestDamagedLastLogRecord(t *testing.T) {
+	dir := t.TempDir()
+	q, _ := Open[int, heap.Min](dir, heap.JSONCodec[int]{}, Options{})
+	q.Push(2)
+	q.Push(1)
+	q.Close()
+
+	// a complete record with a bad checksum at the end of the log is torn
+	path := filepath.Join(dir, logName(0))
+	data, _ := os.ReadFile(path)
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(path, data, 0o644)
+
+	q, err := Open[int, heap.Min](dir, heap.JSONCodec[int]{}, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	defer q.Close()
+	if res := popAll(t, q); !slices.Equal(res, []int{2}) {
+		t.Errorf("Unexpected elements after reopening: %v\n", res)
+	}
+}
+
+func TestCorruptSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	q, _ := Open[int, heap.Min](dir, heap.JSONCodec[int]{}, Options{})
+	q.Push(1)
+	q.Compact()
+	q.Close()
+
+	path := filepath.Join(dir, snapshotName)
+	data, _ := os.ReadFile(path)
+	data[len(data)-5] ^= 0xff
+	os.WriteFile(path, data, 0o644)
+
+	if _, err := Open[int, heap.Min](dir, heap.JSONCodec[int]{}, Options{}); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt, got %v\n", err)
+	}
+}
+
+// TestCrashRecovery runs itself in a subprocess which pushes and pops some
+// elements and then exits without closing the queue. The parent then reopens
+// the queue and checks that nothing was lost.
+func TestCrashRecovery(t *testing.T) {
+	if dir := os.Getenv("DURABLE_TEST_CRASH_DIR"); dir != "" {
+		q, err := Open[int, heap.Max](dir, heap.JSONCodec[int]{}, Options{CompactAfter: 50})
+		if err != nil {
+			os.Exit(2)
+		}
+		for i := 0; i < 200; i++ {
+			q.Push(i)
+			if i%4 == 0 {
+				q.Pop()
+			}
+		}
+		os.Exit(0)
+	}
+
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCrashRecovery$")
+	cmd.Env = append(os.Environ(), "DURABLE_TEST_CRASH_DIR="+dir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Subprocess failed: %v\n%s\n", err, out)
+	}
+
+	var expected heap.Heap[int, heap.Max]
+	for i := 0; i < 200; i++ {
+		heap.Push(&expected, i)
+		if i%4 == 0 {
+			heap.Pop(&expected)
+		}
+	}
+
+	q, err := Open[int, heap.Max](dir, heap.JSONCodec[int]{}, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	defer q.Close()
+	if res := popAll(t, q); !slices.Equal(res, slices.Collect(heap.Drain(&expected))) {
+		t.Errorf("Unexpected elements after crash: %v\n", res)
+	}
+}
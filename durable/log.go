@@ -0,0 +1,215 @@
+package durable
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Each log record consists of a one byte record type, the uvarint-encoded
+// length of the payload, the payload, and a CRC-32C checksum of everything
+// preceding it in the record.
+const (
+	opPush byte = 1
+	opPop  byte = 2
+)
+
+const logPrefix = "wal-"
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+func logName(gen uint64) string {
+	return fmt.Sprintf("%s%016x", logPrefix, gen)
+}
+
+type logWriter struct {
+	f       *os.File
+	records int
+	buf     []byte
+}
+
+func openLogWriter(path string, records int) (*logWriter, error) {
+	_, statErr := os.Stat(path)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if errors.Is(statErr, os.ErrNotExist) {
+		if err := syncDir(filepath.Dir(path)); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return &logWriter{f: f, records: records}, nil
+}
+
+func (w *logWriter) append(op byte, payload []byte) error {
+	w.buf = append(w.buf[:0], op)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(payload)))
+	w.buf = append(w.buf, payload...)
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, crc32.Checksum(w.buf, crcTable))
+	if _, err := w.f.Write(w.buf); err != nil {
+		return err
+	}
+	w.records++
+	return nil
+}
+
+func (w *logWriter) sync() error {
+	return w.f.Sync()
+}
+
+func (w *logWriter) close() error {
+	return w.f.Close()
+}
+
+// replayLog calls apply for each record in the log at path and returns the
+// number of records. A missing log is treated as empty. If the log ends with an
+// incomplete or damaged record (as may be left behind by a crash during a
+// write) then the log is truncated to remove it. A damaged record that is
+// followed by more data is corruption, and the log is left as it is.
+func replayLog(path string, apply func(op byte, payload []byte) error) (int, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	r := bufio.NewReader(f)
+	var good int64
+	var records int
+	var rec []byte
+	for {
+		rec, err = readRecord(r, good, fi.Size(), rec[:0])
+		if err == io.EOF {
+			return records, nil
+		}
+		if err == errTornRecord {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if err := apply(rec[0], rec[1:]); err != nil {
+			return 0, err
+		}
+		good += recordLen(len(rec) - 1)
+		records++
+	}
+
+	// discard the torn record at the end of the log
+	if err := os.Truncate(path, good); err != nil {
+		return 0, err
+	}
+	return records, nil
+}
+
+var errTornRecord = errors.New("torn record")
+
+// readRecord reads the record at offset off of a log of the given size, and
+// returns its type byte followed by its payload. Returns io.EOF if there are no
+// more records and errTornRecord if the record is the last in the log and is
+// incomplete or damaged.
+func readRecord(r *bufio.Reader, off, size int64, rec []byte) ([]byte, error) {
+	corrupt := func(what string) error {
+		return fmt.Errorf("%w: log record at offset %v %v", ErrCorrupt, off, what)
+	}
+
+	op, err := r.ReadByte()
+	if err != nil {
+		return rec, err
+	}
+	n, err := binary.ReadUvarint(r)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return rec, errTornRecord
+	}
+	if err != nil {
+		return rec, corrupt("has bad length")
+	}
+	if n > uint64(size-off) {
+		return rec, errTornRecord
+	}
+	end := off + recordLen(int(n))
+	if end > size {
+		return rec, errTornRecord
+	}
+
+	rec = slices.Grow(rec[:0], int(n)+1)[:n+1]
+	rec[0] = op
+	if _, err := io.ReadFull(r, rec[1:]); err != nil {
+		return rec, errTornRecord
+	}
+	var sum [4]byte
+	if _, err := io.ReadFull(r, sum[:]); err != nil {
+		return rec, errTornRecord
+	}
+
+	crc := crc32.Update(0, crcTable, rec[:1])
+	crc = crc32.Update(crc, crcTable, binary.AppendUvarint(nil, n))
+	crc = crc32.Update(crc, crcTable, rec[1:])
+	if crc != binary.LittleEndian.Uint32(sum[:]) {
+		if end == size {
+			return rec, errTornRecord
+		}
+		return rec, corrupt("has bad checksum")
+	}
+	return rec, nil
+}
+
+// recordLen returns the length of a record with a payload of n bytes.
+func recordLen(n int) int64 {
+	return 1 + int64(uvarintLen(uint64(n))) + int64(n) + 4
+}
+
+func uvarintLen(v uint64) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], v)
+}
+
+// removeStaleLogs removes logs older than gen, which have been superseded by
+// the snapshot.
+func removeStaleLogs(dir string, gen uint64) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, logPrefix) {
+			continue
+		}
+		g, err := strconv.ParseUint(strings.TrimPrefix(name, logPrefix), 16, 64)
+		if err != nil || g >= gen {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
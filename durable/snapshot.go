@@ -0,0 +1,127 @@
+package durable
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/Mishka-Squat/heap"
+)
+
+// A snapshot consists of a magic string, a version byte, the uvarint-encoded
+// generation of the log that follows the snapshot, the uvarint-encoded number
+// of elements, each element as a uvarint-encoded length followed by the encoded
+// element, and finally a CRC-32C checksum of everything preceding it.
+const (
+	snapshotName    = "snapshot"
+	snapshotMagic   = "HEAPSNAP"
+	snapshotVersion = 1
+)
+
+// readSnapshot returns the log generation and elements recorded in the
+// snapshot at path. A missing snapshot is treated as an empty queue with log
+// generation 0.
+func readSnapshot[T any](path string, codec heap.Codec[T]) (gen uint64, elems []T, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	corrupt := func(what string) error {
+		return fmt.Errorf("%w: snapshot %v", ErrCorrupt, what)
+	}
+
+	if len(data) < len(snapshotMagic)+1+4 || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return 0, nil, corrupt("has bad header")
+	}
+	if v := data[len(snapshotMagic)]; v != snapshotVersion {
+		return 0, nil, corrupt(fmt.Sprintf("has unsupported version %v", v))
+	}
+	body, sum := data[:len(data)-4], data[len(data)-4:]
+	if crc32.Checksum(body, crcTable) != binary.LittleEndian.Uint32(sum) {
+		return 0, nil, corrupt("has bad checksum")
+	}
+
+	r := bytes.NewReader(body[len(snapshotMagic)+1:])
+	if gen, err = binary.ReadUvarint(r); err != nil {
+		return 0, nil, corrupt("is truncated")
+	}
+	n, err := binary.ReadUvarint(r)
+	if err != nil || n > uint64(r.Len()) {
+		return 0, nil, corrupt("is truncated")
+	}
+	elems = make([]T, 0, n)
+	var buf []byte
+	for range n {
+		l, err := binary.ReadUvarint(r)
+		if err != nil || l > uint64(r.Len()) {
+			return 0, nil, corrupt("is truncated")
+		}
+		buf = slices.Grow(buf[:0], int(l))[:l]
+		r.Read(buf)
+		v, err := codec.Decode(buf)
+		if err != nil {
+			return 0, nil, fmt.Errorf("%w: %w", ErrCorrupt, err)
+		}
+		elems = append(elems, v)
+	}
+	return gen, elems, nil
+}
+
+// writeSnapshot atomically replaces the snapshot in dir.
+func writeSnapshot[T any](dir string, gen uint64, n int, elems iter.Seq[T], codec heap.Codec[T]) error {
+	tmp := filepath.Join(dir, snapshotName+".tmp")
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+
+	crc := crc32.New(crcTable)
+	w := bufio.NewWriter(io.MultiWriter(f, crc))
+
+	var buf []byte
+	buf = append(buf, snapshotMagic...)
+	buf = append(buf, snapshotVersion)
+	buf = binary.AppendUvarint(buf, gen)
+	buf = binary.AppendUvarint(buf, uint64(n))
+	w.Write(buf)
+
+	var enc []byte
+	for v := range elems {
+		enc, err = codec.Append(enc[:0], v)
+		if err != nil {
+			return err
+		}
+		w.Write(binary.AppendUvarint(buf[:0], uint64(len(enc))))
+		w.Write(enc)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if _, err := f.Write(binary.LittleEndian.AppendUint32(nil, crc.Sum32())); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, snapshotName)); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
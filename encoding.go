@@ -0,0 +1,92 @@
+package heap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// The first byte of the binary encoding of a Heap. Increment this if the
+// encoding changes.
+const binaryEncodingVersion = 1
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding consists of a
+// version byte followed by the gob encoding of the heap's elements in the order
+// given by the underlying slice, so T must be encodable by encoding/gob.
+func (heap *Heap[T, MOM]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(binaryEncodingVersion)
+	if err := gob.NewEncoder(&buf).Encode(heap.sl); err != nil {
+		return nil, fmt.Errorf("heap: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The previous contents
+// of the heap (if any) are discarded. The decoded elements are rebuilt into a
+// heap using Floyd's algorithm (as for FromSlice), so the heap property holds
+// even if the data has been modified since it was encoded. T must either
+// satisfy constraints.Ordered or implement Orderable.
+func (heap *Heap[T, MOM]) UnmarshalBinary(data []byte) error {
+	cmp, err := runtimeCmp[T]()
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.New("heap: missing encoding version")
+	}
+	if data[0] != binaryEncodingVersion {
+		return fmt.Errorf("heap: unsupported encoding version %v", data[0])
+	}
+
+	var sl []T
+	if err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&sl); err != nil {
+		return fmt.Errorf("heap: %w", err)
+	}
+	fromSlice(heap, sl, func(i, j int) int { return cmp(heap.sl[i], heap.sl[j]) })
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder. It is equivalent to MarshalBinary.
+func (heap *Heap[T, MOM]) GobEncode() ([]byte, error) {
+	return heap.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder. It is equivalent to UnmarshalBinary.
+func (heap *Heap[T, MOM]) GobDecode(data []byte) error {
+	return heap.UnmarshalBinary(data)
+}
+
+// runtimeCmp returns a comparison function for T for use in contexts (such as
+// interface methods) where T cannot be constrained to c.Ordered or
+// Orderable[T] at compile time.
+func runtimeCmp[T any]() (func(a, b T) int, error) {
+	var zero T
+	if _, ok := any(zero).(Orderable[T]); ok {
+		return func(a, b T) int { return any(a).(Orderable[T]).Cmp(b) }, nil
+	}
+
+	// Handle types with an underlying type that satisfies c.Ordered.
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b T) int {
+			return cmpOrdered(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b T) int {
+			return cmpOrdered(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(a, b T) int {
+			return cmpOrdered(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		}, nil
+	case reflect.String:
+		return func(a, b T) int {
+			return cmpOrdered(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		}, nil
+	}
+
+	return nil, fmt.Errorf("heap: %v neither satisfies constraints.Ordered nor implements Orderable", reflect.TypeFor[T]())
+}
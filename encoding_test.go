@@ -0,0 +1,138 @@
+package heap
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"slices"
+	"testing"
+)
+
+var _ encoding.BinaryMarshaler = (*Heap[int, Min])(nil)
+var _ encoding.BinaryUnmarshaler = (*Heap[int, Min])(nil)
+var _ gob.GobEncoder = (*Heap[int, Min])(nil)
+var _ gob.GobDecoder = (*Heap[int, Min])(nil)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14}
+	var heap Heap[int, Max]
+	for _, elem := range elems {
+		Push(&heap, elem)
+	}
+	data, err := heap.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if data[0] != binaryEncodingVersion {
+		t.Errorf("Expected version byte %v, got %v\n", binaryEncodingVersion, data[0])
+	}
+	var decoded Heap[int, Max]
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if !slices.Equal(decoded.sl, heap.sl) {
+		t.Errorf("Expected decoded heap to have the same layout: %v %v\n", decoded.sl, heap.sl)
+	}
+}
+
+func TestBinaryEmpty(t *testing.T) {
+	var heap Heap[int, Min]
+	data, err := heap.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	decoded := Heap[int, Min]{sl: []int{1, 2, 3}}
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if decoded.sl != nil {
+		t.Errorf("Expected decoded heap to have nil backing slice, got %v\n", decoded.sl)
+	}
+}
+
+func TestBinaryRebuildsHeap(t *testing.T) {
+	// Encode a slice that doesn't have the heap property by bypassing Push.
+	bad := Heap[int, Min]{sl: []int{9, 8, 7, 6, 5, 4, 3, 2, 1}}
+	data, err := bad.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	var decoded Heap[int, Min]
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if !checkMinHeapProperty(&decoded, 0) {
+		t.Errorf("Min heap property violated: %v\n", decoded.sl)
+	}
+}
+
+func TestBinaryErrors(t *testing.T) {
+	var heap Heap[int, Min]
+	if err := heap.UnmarshalBinary(nil); err == nil {
+		t.Errorf("Expected error for empty data")
+	}
+	if err := heap.UnmarshalBinary([]byte{99}); err == nil {
+		t.Errorf("Expected error for unknown version")
+	}
+	if err := heap.UnmarshalBinary([]byte{binaryEncodingVersion, 1, 2, 3}); err == nil {
+		t.Errorf("Expected error for corrupted data")
+	}
+
+	type unordered struct{ X int }
+	var heap2 Heap[unordered, Min]
+	data, _ := (&Heap[int, Min]{}).MarshalBinary()
+	if err := heap2.UnmarshalBinary(data); err == nil {
+		t.Errorf("Expected error for element type without an ordering")
+	}
+}
+
+type namedPriority uint16
+
+func TestBinaryNamedOrderedType(t *testing.T) {
+	var heap Heap[namedPriority, Max]
+	for _, p := range []namedPriority{3, 1, 4, 1, 5} {
+		Push(&heap, p)
+	}
+	data, err := heap.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	var decoded Heap[namedPriority, Max]
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if v, _ := Pop(&decoded); v != 5 {
+		t.Errorf("Expected 5, got %v\n", v)
+	}
+}
+
+func TestGobOrderable(t *testing.T) {
+	type checkpoint struct {
+		Name  string
+		Tasks Heap[myCustomType, Min]
+	}
+	var cp checkpoint
+	cp.Name = "scheduler"
+	PushOrderable(&cp.Tasks, myCustomType{Key: 2, Content: "b"})
+	PushOrderable(&cp.Tasks, myCustomType{Key: 1, Content: "a"})
+	PushOrderable(&cp.Tasks, myCustomType{Key: 3, Content: "c"})
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&cp); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	var decoded checkpoint
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if decoded.Name != "scheduler" {
+		t.Errorf("Unexpected name: %v\n", decoded.Name)
+	}
+	var s string
+	for v := range DrainOrderable(&decoded.Tasks) {
+		s += v.Content
+	}
+	if s != "abc" {
+		t.Errorf("Unexpected pop order: %v\n", s)
+	}
+}
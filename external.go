@@ -0,0 +1,324 @@
+package heap
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+
+	c "golang.org/x/exp/constraints"
+)
+
+// ExternalOptions configure an ExternalHeap. The zero value gives the default
+// options.
+type ExternalOptions struct {
+	// MaxInMemory is the maximum number of elements held in memory. When the
+	// in-memory heap is full, its contents are written to a temporary file as a
+	// sorted run. Defaults to 1<<20.
+	MaxInMemory int
+	// MaxRuns is the maximum number of sorted runs (and hence open files). When
+	// it is exceeded, all of the runs are merged into a single run. Defaults to
+	// 64.
+	MaxRuns int
+	// TempDir is the directory in which run files are created. Defaults to
+	// os.TempDir().
+	TempDir string
+}
+
+const (
+	defaultExternalMaxInMemory = 1 << 20
+	defaultExternalMaxRuns     = 64
+)
+
+// ExternalHeap is a min or max heap that can hold more elements than fit in
+// memory. At most ExternalOptions.MaxInMemory elements are held in an in-memory
+// Heap; when it fills up, its elements are spilled to a temporary file as a
+// sorted run. Pop transparently merges the in-memory heap and the runs.
+// Elements are written to the run files using a Codec.
+//
+// Unlike Heap, the default value of ExternalHeap is not usable. Use NewExternal
+// or NewExternalOrderable to create an ExternalHeap, and call Close to remove
+// its temporary files once it's no longer needed.
+type ExternalHeap[T any, MOM MinOrMax] struct {
+	opts  ExternalOptions
+	codec Codec[T]
+	cmp   func(a, b T) int
+	mem   Heap[T, MOM]
+	// runs is ordered by the next element of each run
+	runs Heap[*externalRun[T], MOM]
+	n    int
+	rbuf []byte
+	wbuf []byte
+}
+
+type externalRun[T any] struct {
+	f         *os.File
+	r         *bufio.Reader
+	head      T
+	remaining int
+}
+
+// NewExternal returns an empty ExternalHeap for a T that satisfies
+// constraints.Ordered.
+func NewExternal[T c.Ordered, MOM MinOrMax](codec Codec[T], opts ExternalOptions) *ExternalHeap[T, MOM] {
+	return newExternal[T, MOM](codec, opts, cmpOrdered[T])
+}
+
+// NewExternalOrderable returns an empty ExternalHeap for a T that implements
+// Orderable.
+func NewExternalOrderable[T Orderable[T], MOM MinOrMax](codec Codec[T], opts ExternalOptions) *ExternalHeap[T, MOM] {
+	return newExternal[T, MOM](codec, opts, T.Cmp)
+}
+
+func newExternal[T any, MOM MinOrMax](codec Codec[T], opts ExternalOptions, cmp func(a, b T) int) *ExternalHeap[T, MOM] {
+	if opts.MaxInMemory <= 0 {
+		opts.MaxInMemory = defaultExternalMaxInMemory
+	}
+	if opts.MaxRuns <= 0 {
+		opts.MaxRuns = defaultExternalMaxRuns
+	}
+	return &ExternalHeap[T, MOM]{opts: opts, codec: codec, cmp: cmp}
+}
+
+func (e *ExternalHeap[T, MOM]) memCmp(i, j int) int {
+	return e.cmp(e.mem.sl[i], e.mem.sl[j])
+}
+
+func (e *ExternalHeap[T, MOM]) runsCmp(i, j int) int {
+	return e.cmp(e.runs.sl[i].head, e.runs.sl[j].head)
+}
+
+// Len returns the number of elements in the heap, including those that have
+// been spilled to disk.
+func (e *ExternalHeap[T, MOM]) Len() int {
+	return e.n
+}
+
+// Push adds an element to the heap. If the in-memory heap is full then its
+// contents are first spilled to disk. If an error is returned then elem has not
+// been added, but no existing elements are lost.
+func (e *ExternalHeap[T, MOM]) Push(elem T) error {
+	if len(e.mem.sl) >= e.opts.MaxInMemory {
+		if err := e.spill(); err != nil {
+			return err
+		}
+	}
+	push(&e.mem, elem, e.memCmp)
+	e.n++
+	return nil
+}
+
+// Peek returns the min/max element from the heap without removing it.
+func (e *ExternalHeap[T, MOM]) Peek() (val T, ok bool) {
+	if e.n == 0 {
+		return
+	}
+	if e.popFromRun() {
+		return e.runs.sl[0].head, true
+	}
+	return e.mem.sl[0], true
+}
+
+// Pop removes the min/max element from the heap. The second return value is
+// false if the heap is empty. If reading the next element of a run fails then
+// the popped element is still returned, along with the error, but the rest of
+// that run's elements are lost and no longer counted by Len.
+func (e *ExternalHeap[T, MOM]) Pop() (val T, ok bool, err error) {
+	if e.n == 0 {
+		return
+	}
+	if !e.popFromRun() {
+		val, ok = pop(&e.mem, e.memCmp)
+		e.n--
+		return
+	}
+
+	run := e.runs.sl[0]
+	val, ok = run.head, true
+	e.n--
+	if run.remaining == 0 {
+		pop(&e.runs, e.runsCmp)
+		err = closeExternalRun(run)
+		return
+	}
+	if err = e.advance(run); err != nil {
+		e.n -= run.remaining
+		pop(&e.runs, e.runsCmp)
+		closeExternalRun(run)
+		return
+	}
+	// run.head has changed, so restore the heap property
+	replace(&e.runs, run, e.runsCmp)
+	return
+}
+
+// Close removes all of the heap's temporary files and empties the heap.
+func (e *ExternalHeap[T, MOM]) Close() error {
+	var errs []error
+	for _, run := range e.runs.sl {
+		errs = append(errs, closeExternalRun(run))
+	}
+	Clear(&e.runs)
+	Clear(&e.mem)
+	e.n = 0
+	return errors.Join(errs...)
+}
+
+func (e *ExternalHeap[T, MOM]) popFromRun() bool {
+	var mom MOM
+
+	if len(e.runs.sl) == 0 {
+		return false
+	}
+	if len(e.mem.sl) == 0 {
+		return true
+	}
+	return mom.mul()*e.cmp(e.runs.sl[0].head, e.mem.sl[0]) < 0
+}
+
+// spill writes the contents of the in-memory heap to a new run. If this fails
+// then the in-memory heap keeps its elements.
+func (e *ExternalHeap[T, MOM]) spill() error {
+	// A slice sorted into min/max order is still a valid heap, so sorting in
+	// place loses nothing if the write fails.
+	sorted := e.mem.sl
+	heapSort[T, MOM](sorted, e.cmp)
+	i := 0
+	run, err := e.writeRun(len(sorted), func() T {
+		i++
+		return sorted[i-1]
+	})
+	if err != nil {
+		return err
+	}
+	Reset(&e.mem)
+	push(&e.runs, run, e.runsCmp)
+
+	if len(e.runs.sl) > e.opts.MaxRuns {
+		return e.mergeRuns()
+	}
+	return nil
+}
+
+// mergeRuns merges all of the runs into a single run. The runs are read through
+// independent cursors, so if the merge fails then the existing runs are left
+// as they were.
+func (e *ExternalHeap[T, MOM]) mergeRuns() error {
+	var cursors Heap[*externalRun[T], MOM]
+	cursorsCmp := func(i, j int) int { return e.cmp(cursors.sl[i].head, cursors.sl[j].head) }
+	n := 0
+	for _, run := range e.runs.sl {
+		cursor, err := newExternalCursor(run)
+		if err != nil {
+			return err
+		}
+		push(&cursors, cursor, cursorsCmp)
+		n += run.remaining + 1
+	}
+
+	var readErr error
+	merged, err := e.writeRun(n, func() (v T) {
+		if len(cursors.sl) == 0 {
+			// a previous read failed
+			return
+		}
+		cursor := cursors.sl[0]
+		v = cursor.head
+		if cursor.remaining == 0 {
+			pop(&cursors, cursorsCmp)
+		} else if err := e.advance(cursor); err != nil {
+			readErr = err
+			Clear(&cursors)
+		} else {
+			replace(&cursors, cursor, cursorsCmp)
+		}
+		return v
+	})
+	if err == nil && readErr != nil {
+		closeExternalRun(merged)
+		err = readErr
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, run := range e.runs.sl {
+		closeExternalRun(run)
+	}
+	Clear(&e.runs)
+	push(&e.runs, merged, e.runsCmp)
+	return nil
+}
+
+// newExternalCursor returns a copy of run that reads the rest of the run's file
+// without changing the file offset used by run.
+func newExternalCursor[T any](run *externalRun[T]) (*externalRun[T], error) {
+	off, err := run.f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	off -= int64(run.r.Buffered())
+	fi, err := run.f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	r := io.NewSectionReader(run.f, off, fi.Size()-off)
+	return &externalRun[T]{f: run.f, r: bufio.NewReader(r), head: run.head, remaining: run.remaining}, nil
+}
+
+// writeRun writes n elements, obtained by calling next, to a new run file and
+// returns the run, ready for reading.
+func (e *ExternalHeap[T, MOM]) writeRun(n int, next func() T) (_ *externalRun[T], err error) {
+	f, err := os.CreateTemp(e.opts.TempDir, "heap-run-*")
+	if err != nil {
+		return nil, err
+	}
+	run := &externalRun[T]{f: f, remaining: n}
+	defer func() {
+		if err != nil {
+			closeExternalRun(run)
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	for range n {
+		if e.wbuf, err = writeFramed(w, e.codec, e.wbuf, next()); err != nil {
+			return nil, err
+		}
+	}
+	if err = w.Flush(); err != nil {
+		return nil, err
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	run.r = bufio.NewReader(f)
+	if n > 0 {
+		if err = e.advance(run); err != nil {
+			return nil, err
+		}
+	}
+	return run, nil
+}
+
+// advance reads the next element of the run into run.head.
+func (e *ExternalHeap[T, MOM]) advance(run *externalRun[T]) (err error) {
+	run.head, e.rbuf, err = readFramed(run.r, e.codec, e.rbuf)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	run.remaining--
+	return nil
+}
+
+func closeExternalRun[T any](run *externalRun[T]) error {
+	err := run.f.Close()
+	if rerr := os.Remove(run.f.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
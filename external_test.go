@@ -0,0 +1,248 @@
+package heap
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestExternalPushAndPop(t *testing.T) {
+	src := rand.NewSource(123)
+	dir := t.TempDir()
+	e := NewExternal[int, Min](JSONCodec[int]{}, ExternalOptions{MaxInMemory: 16, MaxRuns: 4, TempDir: dir})
+	defer e.Close()
+
+	elems := make([]int, 1000)
+	for i := range elems {
+		elems[i] = int(src.Int63() % 500)
+		if err := e.Push(elems[i]); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+	}
+	if e.Len() != len(elems) {
+		t.Errorf("Expected length %v, got %v\n", len(elems), e.Len())
+	}
+	if len(e.runs.sl) == 0 || len(e.runs.sl) > 4 {
+		t.Errorf("Expected between 1 and 4 runs, got %v\n", len(e.runs.sl))
+	}
+
+	sort.Ints(elems)
+	for _, expected := range elems {
+		if v, ok := e.Peek(); !ok || v != expected {
+			t.Fatalf("Expected to peek (%v,true), got (%v,%v)\n", expected, v, ok)
+		}
+		v, ok, err := e.Pop()
+		if err != nil || !ok || v != expected {
+			t.Fatalf("Expected (%v,true,nil), got (%v,%v,%v)\n", expected, v, ok, err)
+		}
+	}
+	if _, ok, _ := e.Pop(); ok {
+		t.Errorf("Calling Pop on an empty heap should have returned ok=false")
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 0 {
+		t.Errorf("Expected exhausted runs to be removed, found %v files\n", len(files))
+	}
+}
+
+func TestExternalOrderableMax(t *testing.T) {
+	e := NewExternalOrderable[myCustomType, Max](JSONCodec[myCustomType]{}, ExternalOptions{MaxInMemory: 3, TempDir: t.TempDir()})
+	defer e.Close()
+	for _, k := range []int{3, 1, 4, 1, 5, 9, 2, 6, 5, 3} {
+		e.Push(myCustomType{Key: k})
+	}
+	var keys []int
+	for {
+		v, ok, err := e.Pop()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if !ok {
+			break
+		}
+		keys = append(keys, v.Key)
+	}
+	if !slices.Equal(keys, []int{9, 6, 5, 5, 4, 3, 3, 2, 1, 1}) {
+		t.Errorf("Unexpected pop order: %v\n", keys)
+	}
+}
+
+func TestExternalClose(t *testing.T) {
+	dir := t.TempDir()
+	e := NewExternal[int, Min](JSONCodec[int]{}, ExternalOptions{MaxInMemory: 2, TempDir: dir})
+	for i := 0; i < 10; i++ {
+		e.Push(i)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 0 {
+		t.Errorf("Expected Close to remove all run files, found %v files\n", len(files))
+	}
+	if e.Len() != 0 {
+		t.Errorf("Expected empty heap after Close")
+	}
+}
+
+// Fuzz tests a randomly generated sequence of operations against the same set
+// of operations performed on a sorted slice.
+func TestExternalHeapFuzz(t *testing.T) {
+	src := rand.NewSource(123)
+
+	realHeap := NewExternal[int, Min](JSONCodec[int]{}, ExternalOptions{MaxInMemory: 32, MaxRuns: 3, TempDir: t.TempDir()})
+	defer realHeap.Close()
+	var naiveHeap []int
+
+	for i := 0; i < 5000; i++ {
+		rnd := src.Int63()
+		if rnd%5 == 0 {
+			v1, ok1 := naiveHeapPop(&naiveHeap)
+			v2, ok2, err := realHeap.Pop()
+			if err != nil || v1 != v2 || ok1 != ok2 {
+				t.Fatalf("Got %v,%v,%v, expected %v,%v\n", v2, ok2, err, v1, ok1)
+			}
+		} else {
+			v := int(rnd % 100)
+			naiveMinHeapPush(&naiveHeap, v)
+			if err := realHeap.Push(v); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+		}
+		if realHeap.Len() != len(naiveHeap) {
+			t.Fatalf("Expected length %v, got %v\n", len(naiveHeap), realHeap.Len())
+		}
+	}
+
+	for {
+		v1, ok1, err := realHeap.Pop()
+		v2, ok2 := naiveHeapPop(&naiveHeap)
+
+		if err != nil || v1 != v2 || ok1 != ok2 {
+			t.Errorf("Oh no! Got %v,%v,%v, expected %v,%v.\n", v1, ok1, err, v2, ok2)
+			break
+		}
+
+		if !ok1 {
+			break
+		}
+	}
+}
+
+var errFailingCodec = errors.New("failing codec")
+
+// failingCodec is a JSONCodec[int] that fails on the failAppend'th call to
+// Append (counting from 1) and when decoding failDecode, if these are set.
+type failingCodec struct {
+	appends    int
+	failAppend int
+	failDecode *int
+}
+
+func (fc *failingCodec) Append(dst []byte, v int) ([]byte, error) {
+	fc.appends++
+	if fc.appends == fc.failAppend {
+		return dst, errFailingCodec
+	}
+	return JSONCodec[int]{}.Append(dst, v)
+}
+
+func (fc *failingCodec) Decode(data []byte) (int, error) {
+	v, err := JSONCodec[int]{}.Decode(data)
+	if err == nil && fc.failDecode != nil && v == *fc.failDecode {
+		return 0, errFailingCodec
+	}
+	return v, err
+}
+
+func popAllExternal(t *testing.T, e *ExternalHeap[int, Min]) []int {
+	t.Helper()
+	var out []int
+	for {
+		v, ok, err := e.Pop()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if !ok {
+			return out
+		}
+		out = append(out, v)
+	}
+}
+
+func TestExternalSpillFailure(t *testing.T) {
+	dir := t.TempDir()
+	codec := &failingCodec{failAppend: 3}
+	e := NewExternal[int, Min](codec, ExternalOptions{MaxInMemory: 4, TempDir: dir})
+	defer e.Close()
+	for _, v := range []int{5, 3, 1, 4} {
+		if err := e.Push(v); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+	}
+	if err := e.Push(2); !errors.Is(err, errFailingCodec) {
+		t.Fatalf("Expected errFailingCodec, got %v\n", err)
+	}
+	if e.Len() != 4 {
+		t.Errorf("Expected length 4, got %v\n", e.Len())
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("Expected failed run to be removed, found %v files\n", len(files))
+	}
+	if got := popAllExternal(t, e); !slices.Equal(got, []int{1, 3, 4, 5}) {
+		t.Errorf("Expected [1 3 4 5], got %v\n", got)
+	}
+}
+
+func TestExternalMergeFailure(t *testing.T) {
+	dir := t.TempDir()
+	// Three spills of two elements each, then fail partway through the merge
+	codec := &failingCodec{failAppend: 9}
+	e := NewExternal[int, Min](codec, ExternalOptions{MaxInMemory: 2, MaxRuns: 2, TempDir: dir})
+	defer e.Close()
+	for i := range 6 {
+		if err := e.Push(i); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+	}
+	if err := e.Push(6); !errors.Is(err, errFailingCodec) {
+		t.Fatalf("Expected errFailingCodec, got %v\n", err)
+	}
+	if e.Len() != 6 {
+		t.Errorf("Expected length 6, got %v\n", e.Len())
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 3 {
+		t.Errorf("Expected the 3 existing runs to be kept, found %v files\n", len(files))
+	}
+	if got := popAllExternal(t, e); !slices.Equal(got, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("Expected [0 1 2 3 4 5], got %v\n", got)
+	}
+}
+
+func TestExternalPopReadFailure(t *testing.T) {
+	codec := &failingCodec{}
+	e := NewExternal[int, Min](codec, ExternalOptions{MaxInMemory: 4, TempDir: t.TempDir()})
+	defer e.Close()
+	for i := 1; i <= 5; i++ {
+		e.Push(i)
+	}
+	failDecode := 3
+	codec.failDecode = &failDecode
+	if v, ok, err := e.Pop(); err != nil || !ok || v != 1 {
+		t.Fatalf("Expected (1,true,nil), got (%v,%v,%v)\n", v, ok, err)
+	}
+	if v, ok, err := e.Pop(); !errors.Is(err, errFailingCodec) || !ok || v != 2 {
+		t.Fatalf("Expected (2,true,errFailingCodec), got (%v,%v,%v)\n", v, ok, err)
+	}
+	// 3 and 4 are lost along with the run
+	if e.Len() != 1 {
+		t.Errorf("Expected length 1, got %v\n", e.Len())
+	}
+	if got := popAllExternal(t, e); !slices.Equal(got, []int{5}) {
+		t.Errorf("Expected [5], got %v\n", got)
+	}
+}
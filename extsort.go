@@ -0,0 +1,286 @@
+package heap
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"sync"
+
+	c "golang.org/x/exp/constraints"
+)
+
+// SortOptions configure SortStream. The zero value gives the default options.
+type SortOptions struct {
+	// MemLimit is the maximum number of elements in each sorted run. Defaults to
+	// 1<<20.
+	MemLimit int
+	// MaxFanIn is the maximum number of runs (and hence open files) that are
+	// merged at once. If there are more runs than this, they are merged in
+	// several passes. Defaults to 64.
+	MaxFanIn int
+	// Parallelism is the maximum number of runs that are sorted and written
+	// concurrently. Up to Parallelism+1 runs may be held in memory at once.
+	// Defaults to 1.
+	Parallelism int
+	// TempDir is the directory in which run files are created. Defaults to
+	// os.TempDir().
+	TempDir string
+}
+
+const (
+	defaultSortMemLimit = 1 << 20
+	defaultSortMaxFanIn = 64
+)
+
+// SortStream reads a stream of elements written by EncodeStream from r and
+// writes them to w in min/max order, in the same format. T must satisfy
+// constraints.Ordered. The sort is stable.
+//
+// The input is split into runs of at most SortOptions.MemLimit elements, each
+// of which is sorted in memory and written to a temporary file. The runs are
+// then merged using a heap of run cursors. If the input fits into a single run,
+// no temporary files are created. Temporary files are removed before SortStream
+// returns.
+func SortStream[T c.Ordered, MOM MinOrMax](r io.Reader, w io.Writer, codec Codec[T], opts SortOptions) error {
+	return sortStream[T, MOM](r, w, codec, opts, cmpOrdered[T])
+}
+
+// SortStreamOrderable is the equivalent of SortStream for a T that implements
+// Orderable.
+func SortStreamOrderable[T Orderable[T], MOM MinOrMax](r io.Reader, w io.Writer, codec Codec[T], opts SortOptions) error {
+	return sortStream[T, MOM](r, w, codec, opts, T.Cmp)
+}
+
+type streamSorter[T any, MOM MinOrMax] struct {
+	opts  SortOptions
+	codec Codec[T]
+	cmp   func(a, b T) int
+
+	mu sync.Mutex
+	// runs holds the names of the run files in input order
+	runs []string
+	// temps holds the names of all temporary files created so far
+	temps []string
+	err   error
+}
+
+// sortCursor is the read position in a run being merged.
+type sortCursor[T any] struct {
+	r    *bufio.Reader
+	head T
+	// index is the position of the run in the input, used to break ties
+	index int
+}
+
+func sortStream[T any, MOM MinOrMax](r io.Reader, w io.Writer, codec Codec[T], opts SortOptions, cmp func(a, b T) int) (err error) {
+	if opts.MemLimit <= 0 {
+		opts.MemLimit = defaultSortMemLimit
+	}
+	if opts.MaxFanIn < 2 {
+		opts.MaxFanIn = defaultSortMaxFanIn
+	}
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = 1
+	}
+
+	s := &streamSorter[T, MOM]{opts: opts, codec: codec, cmp: cmp}
+	defer func() {
+		for _, name := range s.temps {
+			if rerr := os.Remove(name); err == nil && !errors.Is(rerr, os.ErrNotExist) {
+				err = rerr
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Parallelism)
+	chunk := make([]T, 0, min(opts.MemLimit, 1024))
+	for v, err := range DecodeStream(r, codec) {
+		if err != nil {
+			wg.Wait()
+			return err
+		}
+		chunk = append(chunk, v)
+		if len(chunk) < opts.MemLimit {
+			continue
+		}
+
+		if s.failed() {
+			break
+		}
+		s.mu.Lock()
+		index := len(s.runs)
+		s.runs = append(s.runs, "")
+		s.mu.Unlock()
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(chunk []T) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			s.sortRun(chunk)
+			name, err := s.writeRun(slices.Values(chunk))
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.runs[index] = name
+			if err != nil && s.err == nil {
+				s.err = err
+			}
+		}(chunk)
+		chunk = make([]T, 0, min(opts.MemLimit, 1024))
+	}
+	wg.Wait()
+	if s.err != nil {
+		return s.err
+	}
+
+	if len(s.runs) == 0 {
+		s.sortRun(chunk)
+		return EncodeStream(w, codec, slices.Values(chunk))
+	}
+	if len(chunk) > 0 {
+		s.sortRun(chunk)
+		name, err := s.writeRun(slices.Values(chunk))
+		if err != nil {
+			return err
+		}
+		s.runs = append(s.runs, name)
+	}
+
+	runs := s.runs
+	for len(runs) > opts.MaxFanIn {
+		var next []string
+		for group := range slices.Chunk(runs, opts.MaxFanIn) {
+			name, err := s.mergeToRun(group)
+			if err != nil {
+				return err
+			}
+			next = append(next, name)
+		}
+		runs = next
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := s.merge(runs, bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func (s *streamSorter[T, MOM]) failed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err != nil
+}
+
+func (s *streamSorter[T, MOM]) sortRun(chunk []T) {
+	var mom MOM
+	slices.SortStableFunc(chunk, func(a, b T) int { return mom.mul() * s.cmp(a, b) })
+}
+
+// writeRun writes the elements of seq to a new temporary file and returns its
+// name.
+func (s *streamSorter[T, MOM]) writeRun(seq iter.Seq[T]) (string, error) {
+	f, err := os.CreateTemp(s.opts.TempDir, "heap-sort-*")
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	s.temps = append(s.temps, f.Name())
+	s.mu.Unlock()
+
+	err = EncodeStream(f, s.codec, seq)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return f.Name(), err
+}
+
+// mergeToRun merges the given runs into a new run and returns its name. The
+// merged runs are removed.
+func (s *streamSorter[T, MOM]) mergeToRun(runs []string) (string, error) {
+	f, err := os.CreateTemp(s.opts.TempDir, "heap-sort-*")
+	if err != nil {
+		return "", err
+	}
+	s.temps = append(s.temps, f.Name())
+
+	bw := bufio.NewWriter(f)
+	err = s.merge(runs, bw)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, name := range runs {
+		os.Remove(name)
+	}
+	return f.Name(), nil
+}
+
+// merge merges the given runs and writes the result to w.
+func (s *streamSorter[T, MOM]) merge(runs []string, w *bufio.Writer) (err error) {
+	var mom MOM
+	var cursors Heap[*sortCursor[T], MOM]
+	cursorsCmp := func(i, j int) int {
+		a, b := cursors.sl[i], cursors.sl[j]
+		if r := s.cmp(a.head, b.head); r != 0 {
+			return r
+		}
+		// As for StableHeap, prefer the earlier run for both min and max heaps.
+		return mom.mul() * cmpOrdered(a.index, b.index)
+	}
+
+	var files []*os.File
+	defer func() {
+		for _, f := range files {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+	}()
+
+	var rbuf []byte
+	for i, name := range runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+		cursor := &sortCursor[T]{r: bufio.NewReader(f), index: i}
+		cursor.head, rbuf, err = readFramed(cursor.r, s.codec, rbuf)
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		push(&cursors, cursor, cursorsCmp)
+	}
+
+	var wbuf []byte
+	for len(cursors.sl) > 0 {
+		cursor := cursors.sl[0]
+		if wbuf, err = writeFramed(w, s.codec, wbuf, cursor.head); err != nil {
+			return err
+		}
+		cursor.head, rbuf, err = readFramed(cursor.r, s.codec, rbuf)
+		if err == io.EOF {
+			pop(&cursors, cursorsCmp)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		replace(&cursors, cursor, cursorsCmp)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package heap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func decodeAll[T any](t *testing.T, r io.Reader, codec Codec[T]) []T {
+	t.Helper()
+	var out []T
+	for v, err := range DecodeStream(r, codec) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestEncodeDecodeStream(t *testing.T) {
+	elems := []string{"a", "", "hello", "world"}
+	var buf bytes.Buffer
+	if err := EncodeStream(&buf, JSONCodec[string]{}, slices.Values(elems)); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if got := decodeAll(t, &buf, JSONCodec[string]{}); !slices.Equal(got, elems) {
+		t.Errorf("Expected %v, got %v\n", elems, got)
+	}
+}
+
+func TestDecodeStreamTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	EncodeStream(&buf, JSONCodec[int]{}, slices.Values([]int{100, 200}))
+	data := buf.Bytes()[:buf.Len()-1]
+	var n int
+	var lastErr error
+	for _, err := range DecodeStream(bytes.NewReader(data), JSONCodec[int]{}) {
+		n++
+		lastErr = err
+	}
+	if n != 2 || !errors.Is(lastErr, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected one value then io.ErrUnexpectedEOF, got %v values and %v\n", n, lastErr)
+	}
+}
+
+func TestDecodeStreamCorruptLength(t *testing.T) {
+	for _, tc := range []struct {
+		data     []byte
+		expected error
+	}{
+		// overflows int when converted
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, ErrCorruptStream},
+		// overflows uint64
+		{bytes.Repeat([]byte{0xff}, 11), ErrCorruptStream},
+		// longer than maxFrameLen
+		{binary.AppendUvarint(nil, 1<<40), ErrCorruptStream},
+		// within the limit, but the data isn't there
+		{binary.AppendUvarint(nil, maxFrameLen), io.ErrUnexpectedEOF},
+	} {
+		var n int
+		var lastErr error
+		for _, err := range DecodeStream(bytes.NewReader(tc.data), JSONCodec[int]{}) {
+			n++
+			lastErr = err
+		}
+		if n != 1 || !errors.Is(lastErr, tc.expected) {
+			t.Errorf("Expected %v for % x, got %v values and %v\n", tc.expected, tc.data, n, lastErr)
+		}
+
+		var out bytes.Buffer
+		err := SortStream[int, Min](bytes.NewReader(tc.data), &out, JSONCodec[int]{}, SortOptions{TempDir: t.TempDir()})
+		if !errors.Is(err, tc.expected) {
+			t.Errorf("Expected SortStream to return %v for % x, got %v\n", tc.expected, tc.data, err)
+		}
+	}
+}
+
+func TestDecodeStreamLargeValue(t *testing.T) {
+	elems := []string{strings.Repeat("x", 3*frameChunkLen+5), "y"}
+	var buf bytes.Buffer
+	EncodeStream(&buf, JSONCodec[string]{}, slices.Values(elems))
+	if got := decodeAll(t, &buf, JSONCodec[string]{}); !slices.Equal(got, elems) {
+		t.Errorf("Large value didn't round trip")
+	}
+}
+
+func TestSortStream(t *testing.T) {
+	src := rand.NewSource(123)
+	for _, opts := range []SortOptions{
+		{},
+		{MemLimit: 10},
+		{MemLimit: 7, MaxFanIn: 2},
+		{MemLimit: 13, MaxFanIn: 3, Parallelism: 4},
+	} {
+		dir := t.TempDir()
+		opts.TempDir = dir
+		for _, n := range []int{0, 1, 7, 100, 1000} {
+			elems := make([]int, n)
+			for i := range elems {
+				elems[i] = int(src.Int63() % 200)
+			}
+			var in, out bytes.Buffer
+			EncodeStream(&in, JSONCodec[int]{}, slices.Values(elems))
+			if err := SortStream[int, Min](&in, &out, JSONCodec[int]{}, opts); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			slices.Sort(elems)
+			if got := decodeAll(t, &out, JSONCodec[int]{}); !slices.Equal(got, elems) {
+				t.Errorf("Expected %v, got %v (%+v)\n", elems, got, opts)
+			}
+		}
+		if files, _ := os.ReadDir(dir); len(files) != 0 {
+			t.Errorf("Expected temporary files to be removed, found %v\n", len(files))
+		}
+	}
+}
+
+func TestSortStreamOrderableMaxStable(t *testing.T) {
+	var elems []myCustomType
+	for i := range 60 {
+		elems = append(elems, myCustomType{Key: i % 5, Content: string(rune('a' + i%26))})
+	}
+	var in, out bytes.Buffer
+	EncodeStream(&in, JSONCodec[myCustomType]{}, slices.Values(elems))
+	err := SortStreamOrderable[myCustomType, Max](&in, &out, JSONCodec[myCustomType]{}, SortOptions{MemLimit: 4, MaxFanIn: 3, Parallelism: 2, TempDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	expected := slices.Clone(elems)
+	slices.SortStableFunc(expected, func(a, b myCustomType) int { return b.Key - a.Key })
+	if got := decodeAll(t, &out, JSONCodec[myCustomType]{}); !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v\n", expected, got)
+	}
+}
+
+func TestSortStreamInvalidInput(t *testing.T) {
+	dir := t.TempDir()
+	var in bytes.Buffer
+	EncodeStream(&in, JSONCodec[int]{}, slices.Values([]int{5, 4, 3, 2, 1}))
+	in.WriteString("\x03abc")
+	err := SortStream[int, Min](&in, io.Discard, JSONCodec[int]{}, SortOptions{MemLimit: 2, TempDir: dir})
+	if err == nil {
+		t.Errorf("Expected an error for invalid input")
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("Expected temporary files to be removed, found %v\n", len(files))
+	}
+}
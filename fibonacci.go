@@ -0,0 +1,376 @@
+package heap
+
+import (
+	"github.com/savsgio/gotils/nocopy"
+	c "golang.org/x/exp/constraints"
+)
+
+// FibHeap is a min or max Fibonacci heap (Fredman and Tarjan, 1987). Push,
+// Peek, Meld and DecreaseKeyFib are O(1) amortized, while Pop and DeleteFib
+// are O(log n) amortized. These bounds make FibHeap asymptotically faster than
+// Heap or IndexedHeap for algorithms that perform many decrease-key operations
+// (e.g. Dijkstra's algorithm on dense graphs), though in practice the larger
+// constant factors often outweigh this. The default value of FibHeap is a
+// valid empty heap.
+type FibHeap[T any, MOM MinOrMax] struct {
+	// root is the min/max node of the circular list of roots
+	root *fibNode[T]
+	n    int
+	// byDegree and roots are scratch space for popFib
+	byDegree []*fibNode[T]
+	roots    []*fibNode[T]
+	nocopy.NoCopy
+}
+
+type fibNode[T any] struct {
+	val T
+	// left and right link the node into a circular list of siblings. child is
+	// any one of the node's children.
+	parent, child, left, right *fibNode[T]
+	degree                     int
+	// marked is set when the node has lost a child since it last became a
+	// child of another node
+	marked  bool
+	removed bool
+}
+
+// A FibHandle refers to an element of a FibHeap. A handle is valid until the
+// element it refers to is popped or deleted from the heap or the heap is
+// cleared. Handles remain valid when the heap containing the element is melded
+// into another heap, in which case they refer to the element in the other
+// heap. The zero value of FibHandle is never valid.
+type FibHandle[T any] struct {
+	n *fibNode[T]
+}
+
+// LenFib returns the number of elements in the heap.
+func LenFib[T any, MOM MinOrMax](heap *FibHeap[T, MOM]) int {
+	return heap.n
+}
+
+// PushFib adds an element to the heap for a T that satisfies
+// constraints.Ordered and returns a handle to the element.
+func PushFib[T c.Ordered, MOM MinOrMax](heap *FibHeap[T, MOM], elem T) FibHandle[T] {
+	return pushFib(heap, elem, cmpOrdered[T])
+}
+
+// PushFibOrderable adds an element to the heap for a T that implements
+// Orderable and returns a handle to the element.
+func PushFibOrderable[T Orderable[T], MOM MinOrMax](heap *FibHeap[T, MOM], elem T) FibHandle[T] {
+	return pushFib(heap, elem, T.Cmp)
+}
+
+func pushFib[T any, MOM MinOrMax](heap *FibHeap[T, MOM], elem T, cmp func(a, b T) int) FibHandle[T] {
+	n := &fibNode[T]{val: elem}
+	n.left, n.right = n, n
+	heap.addRoot(n, cmp)
+	heap.n++
+	return FibHandle[T]{n}
+}
+
+// PopFib removes the min/max element from the heap for a T that satisfies
+// constraints.Ordered.
+func PopFib[T c.Ordered, MOM MinOrMax](heap *FibHeap[T, MOM]) (T, bool) {
+	return popFib(heap, cmpOrdered[T])
+}
+
+// PopFibOrderable removes the min/max element from the heap for a T that
+// implements Orderable.
+func PopFibOrderable[T Orderable[T], MOM MinOrMax](heap *FibHeap[T, MOM]) (T, bool) {
+	return popFib(heap, T.Cmp)
+}
+
+func popFib[T any, MOM MinOrMax](heap *FibHeap[T, MOM], cmp func(a, b T) int) (val T, ok bool) {
+	z := heap.root
+	if z == nil {
+		return
+	}
+	ok = true
+	val = z.val
+
+	// move the children of z to the root list
+	if c := z.child; c != nil {
+		x := c
+		for {
+			x.parent = nil
+			x.marked = false
+			x = x.right
+			if x == c {
+				break
+			}
+		}
+		spliceFibLists(z, c)
+	}
+
+	// remove z from the root list
+	if z.right == z {
+		heap.root = nil
+	} else {
+		z.left.right = z.right
+		z.right.left = z.left
+		heap.root = z.right
+		heap.consolidate(cmp)
+	}
+	heap.n--
+
+	*z = fibNode[T]{removed: true}
+	return
+}
+
+// PeekFib returns the min/max element from the heap without removing it.
+func PeekFib[T any, MOM MinOrMax](heap *FibHeap[T, MOM]) (val T, ok bool) {
+	if heap.root == nil {
+		return
+	}
+	ok = true
+	val = heap.root.val
+	return
+}
+
+// GetFib returns the value of the element referred to by the handle. The
+// second return value is false if the handle is not valid.
+func GetFib[T any](h FibHandle[T]) (val T, ok bool) {
+	if h.n == nil || h.n.removed {
+		return
+	}
+	ok = true
+	val = h.n.val
+	return
+}
+
+// MeldFib moves all of the elements of src into dst in O(1) time for a T that
+// satisfies constraints.Ordered. src is left empty. Handles to elements of src
+// remain valid and now refer to elements of dst.
+func MeldFib[T c.Ordered, MOM MinOrMax](dst, src *FibHeap[T, MOM]) {
+	meldFib(dst, src, cmpOrdered[T])
+}
+
+// As for MeldFib, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func MeldFibOrderable[T Orderable[T], MOM MinOrMax](dst, src *FibHeap[T, MOM]) {
+	meldFib(dst, src, T.Cmp)
+}
+
+func meldFib[T any, MOM MinOrMax](dst, src *FibHeap[T, MOM], cmp func(a, b T) int) {
+	if dst == src || src.root == nil {
+		return
+	}
+	dst.addRoot(src.root, cmp)
+	dst.n += src.n
+	src.root = nil
+	src.n = 0
+}
+
+// DecreaseKeyFib replaces the value of the element referred to by the handle
+// with a value that is no further from the root (i.e. no greater for a min heap
+// and no less for a max heap) for a T that satisfies constraints.Ordered. The
+// handle must refer to an element of heap. Returns false without modifying the
+// heap if the handle is not valid or the new value is further from the root
+// than the old one.
+func DecreaseKeyFib[T c.Ordered, MOM MinOrMax](heap *FibHeap[T, MOM], h FibHandle[T], val T) bool {
+	return decreaseKeyFib(heap, h, val, cmpOrdered[T])
+}
+
+// As for DecreaseKeyFib, but for the case where T cannot be compared using <
+// and there is an implementation of Orderable[T].
+func DecreaseKeyFibOrderable[T Orderable[T], MOM MinOrMax](heap *FibHeap[T, MOM], h FibHandle[T], val T) bool {
+	return decreaseKeyFib(heap, h, val, T.Cmp)
+}
+
+func decreaseKeyFib[T any, MOM MinOrMax](heap *FibHeap[T, MOM], h FibHandle[T], val T, cmp func(a, b T) int) bool {
+	var mom MOM
+
+	n := h.n
+	if n == nil || n.removed || mom.mul()*cmp(val, n.val) > 0 {
+		return false
+	}
+
+	n.val = val
+	if p := n.parent; p != nil && mom.mul()*cmp(n.val, p.val) < 0 {
+		heap.cut(n)
+		heap.cascadingCut(p)
+	}
+	if mom.mul()*cmp(n.val, heap.root.val) < 0 {
+		heap.root = n
+	}
+	return true
+}
+
+// DeleteFib removes the element referred to by the handle from the heap for a
+// T that satisfies constraints.Ordered. The handle must refer to an element of
+// heap. The second return value is false if the handle is not valid.
+func DeleteFib[T c.Ordered, MOM MinOrMax](heap *FibHeap[T, MOM], h FibHandle[T]) (T, bool) {
+	return deleteFib(heap, h, cmpOrdered[T])
+}
+
+// As for DeleteFib, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func DeleteFibOrderable[T Orderable[T], MOM MinOrMax](heap *FibHeap[T, MOM], h FibHandle[T]) (T, bool) {
+	return deleteFib(heap, h, T.Cmp)
+}
+
+func deleteFib[T any, MOM MinOrMax](heap *FibHeap[T, MOM], h FibHandle[T], cmp func(a, b T) int) (val T, ok bool) {
+	n := h.n
+	if n == nil || n.removed {
+		return
+	}
+
+	// As if the element's value were decreased to beyond that of any other
+	// element: move it to the root list, make it the root, then pop it.
+	if p := n.parent; p != nil {
+		heap.cut(n)
+		heap.cascadingCut(p)
+	}
+	heap.root = n
+	return popFib(heap, cmp)
+}
+
+// ClearFib empties the heap. All handles to elements of the heap become
+// invalid.
+func ClearFib[T any, MOM MinOrMax](heap *FibHeap[T, MOM]) {
+	// Walk the tree without recursion, as it may be very deep
+	var stack []*fibNode[T]
+	if heap.root != nil {
+		stack = append(stack, heap.root)
+	}
+	for len(stack) > 0 {
+		first := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		x := first
+		for {
+			next := x.right
+			if x.child != nil {
+				stack = append(stack, x.child)
+			}
+			*x = fibNode[T]{removed: true}
+			x = next
+			if x == first {
+				break
+			}
+		}
+	}
+	heap.root = nil
+	heap.n = 0
+	heap.byDegree = nil
+	heap.roots = nil
+}
+
+// addRoot adds the circular list containing n to the root list.
+func (heap *FibHeap[T, MOM]) addRoot(n *fibNode[T], cmp func(a, b T) int) {
+	var mom MOM
+
+	if heap.root == nil {
+		heap.root = n
+		return
+	}
+	spliceFibLists(heap.root, n)
+	if mom.mul()*cmp(n.val, heap.root.val) < 0 {
+		heap.root = n
+	}
+}
+
+// consolidate links roots of the same degree until all roots have distinct
+// degrees, and then finds the new min/max root.
+func (heap *FibHeap[T, MOM]) consolidate(cmp func(a, b T) int) {
+	var mom MOM
+
+	roots := heap.roots[:0]
+	x := heap.root
+	for {
+		roots = append(roots, x)
+		x = x.right
+		if x == heap.root {
+			break
+		}
+	}
+
+	byDegree := heap.byDegree
+	for _, x := range roots {
+		d := x.degree
+		for d < len(byDegree) && byDegree[d] != nil {
+			y := byDegree[d]
+			if mom.mul()*cmp(y.val, x.val) < 0 {
+				x, y = y, x
+			}
+			linkFib(y, x)
+			byDegree[d] = nil
+			d++
+		}
+		for d >= len(byDegree) {
+			byDegree = append(byDegree, nil)
+		}
+		byDegree[d] = x
+	}
+
+	// rebuild the root list from the remaining roots
+	heap.root = nil
+	for i, x := range byDegree {
+		if x == nil {
+			continue
+		}
+		byDegree[i] = nil
+		x.left, x.right = x, x
+		heap.addRoot(x, cmp)
+	}
+
+	clear(roots)
+	heap.roots = roots
+	heap.byDegree = byDegree
+}
+
+// cut moves n from its parent's list of children to the root list.
+func (heap *FibHeap[T, MOM]) cut(n *fibNode[T]) {
+	p := n.parent
+	if n.right == n {
+		p.child = nil
+	} else {
+		if p.child == n {
+			p.child = n.right
+		}
+		n.left.right = n.right
+		n.right.left = n.left
+	}
+	p.degree--
+
+	n.left, n.right = n, n
+	n.parent = nil
+	n.marked = false
+	spliceFibLists(heap.root, n)
+}
+
+// cascadingCut cuts n from its parent if it has already lost a child, and
+// repeats for the parent, or marks n otherwise.
+func (heap *FibHeap[T, MOM]) cascadingCut(n *fibNode[T]) {
+	for n.parent != nil {
+		if !n.marked {
+			n.marked = true
+			return
+		}
+		p := n.parent
+		heap.cut(n)
+		n = p
+	}
+}
+
+// linkFib makes the root y a child of the root x. y must already have been
+// removed from the root list (or the root list must be rebuilt afterwards).
+func linkFib[T any](y, x *fibNode[T]) {
+	y.left, y.right = y, y
+	y.parent = x
+	y.marked = false
+	if x.child == nil {
+		x.child = y
+	} else {
+		spliceFibLists(x.child, y)
+	}
+	x.degree++
+}
+
+// spliceFibLists joins two circular lists into one.
+func spliceFibLists[T any](a, b *fibNode[T]) {
+	aRight, bLeft := a.right, b.left
+	a.right = b
+	b.left = a
+	bLeft.right = aRight
+	aRight.left = bLeft
+}
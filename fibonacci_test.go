@@ -0,0 +1,344 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestFibPushAndPop(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14, 0, 3, 3, 7, 22, -8, 11}
+	var heap FibHeap[int, Min]
+	for _, elem := range elems {
+		PushFib(&heap, elem)
+	}
+	if LenFib(&heap) != len(elems) {
+		t.Errorf("Expected heap to have length %v, got %v\n", len(elems), LenFib(&heap))
+	}
+	sort.Ints(elems)
+	for i := 0; i < len(elems); i++ {
+		if v, ok := PeekFib(&heap); !ok || v != elems[i] {
+			t.Errorf("Expected peek of (%v,true), got (%v,%v)\n", elems[i], v, ok)
+		}
+		v, ok := PopFib(&heap)
+		if !ok || v != elems[i] {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", elems[i], v, ok)
+		}
+		if !checkFibHeapProperty(&heap) {
+			t.Fatalf("Fibonacci heap property violated after pop")
+		}
+	}
+	if _, ok := PopFib(&heap); ok {
+		t.Errorf("Calling PopFib on an empty heap should have returned ok=false")
+	}
+}
+
+func TestFibMeld(t *testing.T) {
+	var a, b FibHeap[int, Max]
+	for i := 0; i < 10; i += 2 {
+		PushFib(&a, i)
+	}
+	var handles []FibHandle[int]
+	for i := 1; i < 10; i += 2 {
+		handles = append(handles, PushFib(&b, i))
+	}
+	MeldFib(&a, &b)
+	if LenFib(&a) != 10 || LenFib(&b) != 0 {
+		t.Errorf("Unexpected lengths after meld: %v %v\n", LenFib(&a), LenFib(&b))
+	}
+	if _, ok := PopFib(&b); ok {
+		t.Errorf("Expected source heap to be empty after meld")
+	}
+
+	// handles into b should now refer to elements of a
+	if !DecreaseKeyFib(&a, handles[0], 100) {
+		t.Errorf("Expected DecreaseKeyFib to succeed")
+	}
+
+	expected := []int{100, 9, 8, 7, 6, 5, 4, 3, 2, 0}
+	for _, e := range expected {
+		if v, ok := PopFib(&a); !ok || v != e {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", e, v, ok)
+		}
+	}
+}
+
+func TestFibDecreaseKeyAndDelete(t *testing.T) {
+	var heap FibHeap[int, Min]
+	handles := make([]FibHandle[int], 20)
+	for i := range handles {
+		handles[i] = PushFib(&heap, i*10)
+	}
+	PopFib(&heap) // force the trees to be consolidated
+
+	if !DecreaseKeyFib(&heap, handles[17], 5) {
+		t.Errorf("Expected DecreaseKeyFib to succeed")
+	}
+	if v, _ := PeekFib(&heap); v != 5 {
+		t.Errorf("Expected 5 at root, got %v\n", v)
+	}
+	if DecreaseKeyFib(&heap, handles[17], 6) {
+		t.Errorf("Expected DecreaseKeyFib to fail when increasing the key")
+	}
+	if DecreaseKeyFib(&heap, handles[0], -1) {
+		t.Errorf("Expected DecreaseKeyFib to fail for a popped element")
+	}
+	if !checkFibHeapProperty(&heap) {
+		t.Fatalf("Fibonacci heap property violated after decrease-key")
+	}
+
+	if v, ok := DeleteFib(&heap, handles[12]); !ok || v != 120 {
+		t.Errorf("Expected (120,true), got (%v,%v)\n", v, ok)
+	}
+	if _, ok := DeleteFib(&heap, handles[12]); ok {
+		t.Errorf("Expected DeleteFib to fail for a deleted element")
+	}
+	if _, ok := GetFib(handles[12]); ok {
+		t.Errorf("Expected handle to be invalid after DeleteFib")
+	}
+	if v, ok := DeleteFib(&heap, handles[17]); !ok || v != 5 {
+		t.Errorf("Expected (5,true), got (%v,%v)\n", v, ok)
+	}
+	if !checkFibHeapProperty(&heap) || LenFib(&heap) != 17 {
+		t.Fatalf("Fibonacci heap property violated after delete")
+	}
+
+	ClearFib(&heap)
+	if _, ok := GetFib(handles[5]); ok {
+		t.Errorf("Expected handle to be invalid after ClearFib")
+	}
+	if _, ok := PopFib(&heap); ok || LenFib(&heap) != 0 {
+		t.Errorf("Expected empty heap after clear")
+	}
+}
+
+func TestFibOrderable(t *testing.T) {
+	var a, b FibHeap[myCustomType, Min]
+	PushFibOrderable(&a, myCustomType{Key: 3, Content: "c"})
+	h := PushFibOrderable(&b, myCustomType{Key: 5, Content: "e"})
+	PushFibOrderable(&b, myCustomType{Key: 2, Content: "b"})
+	d := PushFibOrderable(&b, myCustomType{Key: 4, Content: "d"})
+	MeldFibOrderable(&a, &b)
+	DecreaseKeyFibOrderable(&a, h, myCustomType{Key: 1, Content: "a"})
+	DeleteFibOrderable(&a, d)
+	var s string
+	for {
+		v, ok := PopFibOrderable(&a)
+		if !ok {
+			break
+		}
+		s += v.Content
+	}
+	if s != "abc" {
+		t.Errorf("Unexpected pop order: %v\n", s)
+	}
+}
+
+// Fuzz tests a randomly generated sequence of operations against the same set
+// of operations performed on a sorted slice.
+func TestFibHeapFuzz(t *testing.T) {
+	src := rand.NewSource(123)
+
+	var realHeap FibHeap[int, Min]
+	var naiveHeap []int
+	var handles []FibHandle[int]
+
+	for i := 0; i < 10000; i++ {
+		rnd := src.Int63()
+		if rnd%13 == 0 {
+			v1, ok1 := naiveHeapPop(&naiveHeap)
+			v2, ok2 := PopFib(&realHeap)
+			if v1 != v2 || ok1 != ok2 {
+				t.Fatalf("Got %v,%v, expected %v,%v\n", v2, ok2, v1, ok1)
+			}
+		} else if rnd%7 == 0 && len(handles) > 0 {
+			h := handles[int(rnd/7)%len(handles)]
+			old, ok := GetFib(h)
+			if !ok {
+				continue
+			}
+			v := old - int((rnd/91)%20)
+			naiveHeapRemove(&naiveHeap, old)
+			naiveMinHeapPush(&naiveHeap, v)
+			if !DecreaseKeyFib(&realHeap, h, v) {
+				t.Fatalf("Expected DecreaseKeyFib to succeed")
+			}
+		} else if rnd%11 == 0 && len(handles) > 0 {
+			h := handles[int(rnd/11)%len(handles)]
+			old, ok := GetFib(h)
+			if !ok {
+				continue
+			}
+			naiveHeapRemove(&naiveHeap, old)
+			if v, ok := DeleteFib(&realHeap, h); !ok || v != old {
+				t.Fatalf("Expected (%v,true), got (%v,%v)\n", old, v, ok)
+			}
+		} else if rnd%29 == 0 {
+			var other FibHeap[int, Min]
+			for j := 0; j < 5; j++ {
+				v := int((rnd >> j) % 100)
+				naiveMinHeapPush(&naiveHeap, v)
+				handles = append(handles, PushFib(&other, v))
+			}
+			MeldFib(&realHeap, &other)
+		} else {
+			v := int(rnd % 100)
+			naiveMinHeapPush(&naiveHeap, v)
+			handles = append(handles, PushFib(&realHeap, v))
+		}
+
+		if LenFib(&realHeap) != len(naiveHeap) {
+			t.Fatalf("Expected length %v, got %v\n", len(naiveHeap), LenFib(&realHeap))
+		}
+		if !checkFibHeapProperty(&realHeap) {
+			t.Fatalf("Fibonacci heap property violated")
+		}
+	}
+
+	for {
+		v1, ok1 := PopFib(&realHeap)
+		v2, ok2 := naiveHeapPop(&naiveHeap)
+
+		if v1 != v2 || ok1 != ok2 {
+			t.Errorf("Oh no! Got %v,%v, expected %v,%v.\n", v1, ok1, v2, ok2)
+			break
+		}
+
+		if !ok1 {
+			break
+		}
+	}
+}
+
+type weightedEdge struct{ to, weight int }
+
+// randomDenseGraph returns a random graph with n nodes in which each node has
+// edges to a fraction density of the other nodes.
+func randomDenseGraph(n int, density float64) [][]weightedEdge {
+	rng := rand.New(rand.NewSource(123))
+	graph := make([][]weightedEdge, n)
+	for i := range graph {
+		for j := range n {
+			if i != j && rng.Float64() < density {
+				graph[i] = append(graph[i], weightedEdge{j, 1 + rng.Intn(1000)})
+			}
+		}
+	}
+	return graph
+}
+
+func dijkstraFib(graph [][]weightedEdge) []int {
+	var heap FibHeap[distTo, Min]
+	dist := make([]int, len(graph))
+	handles := make([]FibHandle[distTo], len(graph))
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[0] = 0
+	handles[0] = PushFibOrderable(&heap, distTo{0, 0})
+	for {
+		v, ok := PopFibOrderable(&heap)
+		if !ok {
+			return dist
+		}
+		for _, e := range graph[v.node] {
+			d := v.dist + e.weight
+			if dist[e.to] == -1 {
+				dist[e.to] = d
+				handles[e.to] = PushFibOrderable(&heap, distTo{e.to, d})
+			} else if d < dist[e.to] {
+				dist[e.to] = d
+				DecreaseKeyFibOrderable(&heap, handles[e.to], distTo{e.to, d})
+			}
+		}
+	}
+}
+
+func dijkstraIndexed(graph [][]weightedEdge) []int {
+	var heap IndexedHeap[distTo, Min]
+	dist := make([]int, len(graph))
+	handles := make([]Handle[distTo], len(graph))
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[0] = 0
+	handles[0] = PushIndexedOrderable(&heap, distTo{0, 0})
+	for {
+		v, ok := PopIndexedOrderable(&heap)
+		if !ok {
+			return dist
+		}
+		for _, e := range graph[v.node] {
+			d := v.dist + e.weight
+			if dist[e.to] == -1 {
+				dist[e.to] = d
+				handles[e.to] = PushIndexedOrderable(&heap, distTo{e.to, d})
+			} else if d < dist[e.to] {
+				dist[e.to] = d
+				UpdateIndexedOrderable(&heap, handles[e.to], distTo{e.to, d})
+			}
+		}
+	}
+}
+
+// dijkstraLazy uses a binary Heap without decrease-key, pushing a new entry
+// whenever a distance improves and skipping stale entries when they're popped.
+func dijkstraLazy(graph [][]weightedEdge) []int {
+	var heap Heap[distTo, Min]
+	dist := make([]int, len(graph))
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[0] = 0
+	PushOrderable(&heap, distTo{0, 0})
+	for {
+		v, ok := PopOrderable(&heap)
+		if !ok {
+			return dist
+		}
+		if v.dist > dist[v.node] {
+			continue
+		}
+		for _, e := range graph[v.node] {
+			d := v.dist + e.weight
+			if dist[e.to] == -1 || d < dist[e.to] {
+				dist[e.to] = d
+				PushOrderable(&heap, distTo{e.to, d})
+			}
+		}
+	}
+}
+
+func TestFibDijkstra(t *testing.T) {
+	graph := randomDenseGraph(200, 0.3)
+	expected := dijkstraLazy(graph)
+	for i, d := range dijkstraFib(graph) {
+		if d != expected[i] {
+			t.Fatalf("Unexpected distance to %v: expected %v, got %v\n", i, expected[i], d)
+		}
+	}
+	for i, d := range dijkstraIndexed(graph) {
+		if d != expected[i] {
+			t.Fatalf("Unexpected distance to %v: expected %v, got %v\n", i, expected[i], d)
+		}
+	}
+}
+
+func BenchmarkDijkstraDense(b *testing.B) {
+	graph := randomDenseGraph(2000, 0.5)
+	b.Run("FibHeap", func(b *testing.B) {
+		for range b.N {
+			dijkstraFib(graph)
+		}
+	})
+	b.Run("IndexedHeap", func(b *testing.B) {
+		for range b.N {
+			dijkstraIndexed(graph)
+		}
+	})
+	b.Run("HeapLazy", func(b *testing.B) {
+		for range b.N {
+			dijkstraLazy(graph)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package heap
+
+// FuncHeap is a heap ordered by a comparison function supplied at runtime
+// rather than by a type parameter. This is useful when the ordering isn't known
+// until runtime (e.g. a user-selected sort column), or when different heaps of
+// the same T should use different orderings. The element that compares least
+// according to the comparison function is at the root; to obtain a max heap,
+// reverse the comparison.
+//
+// Unlike Heap, the default value of FuncHeap is not usable. Use NewFunc to
+// create a FuncHeap.
+type FuncHeap[T any] struct {
+	heap Heap[T, Min]
+	cmp  func(a, b T) int
+}
+
+// NewFunc returns an empty heap ordered by cmp. The cmp function should return
+// 0 if the two values compare equal, an int < 0 if the first value is less
+// than the second, and an int > 0 otherwise (as for slices.SortFunc).
+func NewFunc[T any](cmp func(a, b T) int) *FuncHeap[T] {
+	return &FuncHeap[T]{cmp: cmp}
+}
+
+func (heap *FuncHeap[T]) cmpIndices(i, j int) int {
+	return heap.cmp(heap.heap.sl[i], heap.heap.sl[j])
+}
+
+// LenFunc returns the number of elements in the heap.
+func LenFunc[T any](heap *FuncHeap[T]) int {
+	return len(heap.heap.sl)
+}
+
+// PushFunc adds an element to the heap.
+func PushFunc[T any](heap *FuncHeap[T], elem T) {
+	push(&heap.heap, elem, heap.cmpIndices)
+}
+
+// PopFunc removes the least element (according to the heap's comparison
+// function) from the heap.
+func PopFunc[T any](heap *FuncHeap[T]) (T, bool) {
+	return pop(&heap.heap, heap.cmpIndices)
+}
+
+// PeekFunc returns the least element (according to the heap's comparison
+// function) without removing it.
+func PeekFunc[T any](heap *FuncHeap[T]) (T, bool) {
+	return Peek(&heap.heap)
+}
+
+// ClearFunc empties the heap. The heap's comparison function is retained.
+func ClearFunc[T any](heap *FuncHeap[T]) {
+	Clear(&heap.heap)
+}
+
+// FilterFunc is the equivalent of Filter for a FuncHeap.
+func FilterFunc[T any](heap *FuncHeap[T], f func(*T) (keepElement bool, breakOrContinue BreakOrContinue)) {
+	filter(&heap.heap, f, heap.cmpIndices)
+}
+
+// FromSliceFunc is the equivalent of FromSlice for a FuncHeap. The heap's
+// comparison function is retained, but its previous contents (if any) are
+// discarded.
+func FromSliceFunc[T any](heap *FuncHeap[T], slice []T) {
+	fromSlice(&heap.heap, slice, heap.cmpIndices)
+}
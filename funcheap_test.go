@@ -0,0 +1,125 @@
+package heap
+
+import (
+	"cmp"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFuncHeapPushAndPop(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14}
+	heap := NewFunc(cmp.Compare[int])
+	for _, elem := range elems {
+		PushFunc(heap, elem)
+	}
+	if LenFunc(heap) != len(elems) {
+		t.Errorf("Expected heap to have length %v, got %v\n", len(elems), LenFunc(heap))
+	}
+	sort.Ints(elems)
+	for i := 0; i < len(elems); i++ {
+		v, ok := PopFunc(heap)
+		if !ok || v != elems[i] {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", elems[i], v, ok)
+		}
+	}
+	if _, ok := PopFunc(heap); ok {
+		t.Errorf("Calling PopFunc on an empty heap should have returned ok=false")
+	}
+}
+
+func TestFuncHeapSameLayoutAsMinHeap(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14}
+	heap := NewFunc(cmp.Compare[int])
+	for _, elem := range elems {
+		PushFunc(heap, elem)
+	}
+	const expected = `
+              -3
+      1               2
+  9       5       17      18
+19  14
+`
+	if layout := debugPrintHeap(&heap.heap); strings.TrimSpace(expected) != strings.TrimSpace(layout) {
+		t.Errorf("Unexpected heap layout:\n%v\n", layout)
+	}
+}
+
+func TestFuncHeapRuntimeOrdering(t *testing.T) {
+	type row struct {
+		name string
+		age  int
+	}
+	rows := []row{{"carol", 35}, {"alice", 40}, {"bob", 25}}
+	columns := map[string]func(a, b row) int{
+		"name": func(a, b row) int { return cmp.Compare(a.name, b.name) },
+		"age":  func(a, b row) int { return cmp.Compare(a.age, b.age) },
+	}
+	expected := map[string]string{
+		"name": "alice bob carol",
+		"age":  "bob carol alice",
+	}
+	for col, f := range columns {
+		heap := NewFunc(f)
+		for _, r := range rows {
+			PushFunc(heap, r)
+		}
+		var names []string
+		for {
+			r, ok := PopFunc(heap)
+			if !ok {
+				break
+			}
+			names = append(names, r.name)
+		}
+		if got := strings.Join(names, " "); got != expected[col] {
+			t.Errorf("Unexpected order for column %v: %v\n", col, got)
+		}
+	}
+}
+
+func TestFuncHeapReversed(t *testing.T) {
+	heap := NewFunc(func(a, b int) int { return cmp.Compare(b, a) })
+	FromSliceFunc(heap, []int{3, 1, 4, 1, 5, 9, 2, 6})
+	if v, ok := PeekFunc(heap); !ok || v != 9 {
+		t.Errorf("Expected (9,true), got (%v,%v)\n", v, ok)
+	}
+}
+
+func TestFuncHeapFilter(t *testing.T) {
+	heap := NewFunc(cmp.Compare[int])
+	for i := 1; i <= 14; i++ {
+		PushFunc(heap, i)
+	}
+	FilterFunc(heap, func(elem *int) (bool, BreakOrContinue) {
+		return (*elem)%3 == 0, Continue
+	})
+	for _, e := range []int{3, 6, 9, 12} {
+		if v, ok := PopFunc(heap); !ok || v != e {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", e, v, ok)
+		}
+	}
+	if LenFunc(heap) != 0 {
+		t.Errorf("Expected empty heap")
+	}
+}
+
+func TestFuncHeapFromSliceFuzz(t *testing.T) {
+	src := rand.NewSource(123)
+	slice := make([]int, 10000)
+	for i := 0; i < len(slice); i++ {
+		slice[i] = int(src.Int63())
+	}
+	sliceCp := make([]int, len(slice))
+	copy(sliceCp, slice)
+	sort.Ints(sliceCp)
+	heap := NewFunc(cmp.Compare[int])
+	FromSliceFunc(heap, slice)
+	for i := 0; i < len(sliceCp); i++ {
+		v, ok := PopFunc(heap)
+		if !ok || v != sliceCp[i] {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", sliceCp[i], ok, v)
+		}
+	}
+}
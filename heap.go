@@ -5,7 +5,8 @@
 // The heap grows in the usual way by appending elements to the backing slice
 // and then making adjustments to preserve the heap property. As the heap
 // shrinks, smaller backing slices are periodically allocated and elements
-// copied over. Empty heaps are guaranteed to be backed by nil slices.
+// copied over (see ShrinkPolicy). Empty heaps are guaranteed to be backed by nil
+// slices, unless the ShrinkNever policy or Reset is used.
 //
 // If your type can be compared using the < operator then you can use the Push
 // and Pop functions to manipulate the heap, e.g.:
@@ -42,7 +43,8 @@ import (
 // shallow copy of the underlying slice, which is likely to give rise to
 // confusing and undesired behavior.
 type Heap[T any, MOM MinOrMax] struct {
-	sl []T
+	sl     []T
+	policy ShrinkPolicy
 	nocopy.NoCopy
 }
 
@@ -143,18 +145,77 @@ func pop[T any, MOM MinOrMax](heap *Heap[T, MOM], cmp func(i, j int) int) (val T
 	i := pushRootHoleDownToLeaf(heap, cmp)
 
 	if i+1 == len(heap.sl) {
-		heap.sl = shrink(heap.sl)
+		heap.sl = shrinkWith(heap.sl, heap.policy)
 		return
 	}
 
 	displaced := heap.sl[len(heap.sl)-1]
-	heap.sl = shrink(heap.sl)
+	heap.sl = shrinkWith(heap.sl, heap.policy)
 	heap.sl[i] = displaced
 	bubble(heap, i, cmp)
 
 	return
 }
 
+// PushPop adds an element to the heap and then removes and returns the min/max
+// element, for a T that satisfies constraints.Ordered. This is more efficient
+// than calling Push followed by Pop, and never grows the heap. If elem would be
+// removed immediately (i.e. if the heap is empty or elem is not greater/less
+// than the min/max element) then elem is returned and the heap is not
+// modified.
+func PushPop[T c.Ordered, MOM MinOrMax](heap *Heap[T, MOM], elem T) T {
+	return pushPop(heap, elem, cmpOrdered[T])
+}
+
+// As for PushPop, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func PushPopOrderable[T Orderable[T], MOM MinOrMax](heap *Heap[T, MOM], elem T) T {
+	return pushPop(heap, elem, T.Cmp)
+}
+
+func pushPop[T any, MOM MinOrMax](heap *Heap[T, MOM], elem T, cmp func(a, b T) int) T {
+	var mom MOM
+
+	if len(heap.sl) == 0 || mom.mul()*cmp(elem, heap.sl[0]) <= 0 {
+		return elem
+	}
+	val, _ := replace(heap, elem, func(i, j int) int { return cmp(heap.sl[i], heap.sl[j]) })
+	return val
+}
+
+// Replace removes the min/max element from the heap and then adds elem to the
+// heap, for a T that satisfies constraints.Ordered. This is more efficient than
+// calling Pop followed by Push, and the heap is not resized. Note that the
+// returned element may be greater/less than elem. If the heap is empty then
+// elem is added and the second return value is false.
+func Replace[T c.Ordered, MOM MinOrMax](heap *Heap[T, MOM], elem T) (T, bool) {
+	return replace(heap, elem, func(i, j int) int { return cmpOrdered(heap.sl[i], heap.sl[j]) })
+}
+
+// As for Replace, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func ReplaceOrderable[T Orderable[T], MOM MinOrMax](heap *Heap[T, MOM], elem T) (T, bool) {
+	return replace(heap, elem, func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	})
+}
+
+func replace[T any, MOM MinOrMax](heap *Heap[T, MOM], elem T, cmp func(i, j int) int) (val T, ok bool) {
+	if len(heap.sl) == 0 {
+		push(heap, elem, cmp)
+		return
+	}
+
+	// As for pop, move the hole left by the root down to a leaf, then fill it
+	// with the new element and bubble it up.
+	ok = true
+	val = heap.sl[0]
+	i := pushRootHoleDownToLeaf(heap, cmp)
+	heap.sl[i] = elem
+	bubble(heap, i, cmp)
+	return
+}
+
 // Peek returns the min/max element from the min/max heap without removing it.
 func Peek[T any, MOM MinOrMax](heap *Heap[T, MOM]) (val T, ok bool) {
 	if len(heap.sl) == 0 {
@@ -165,7 +226,8 @@ func Peek[T any, MOM MinOrMax](heap *Heap[T, MOM]) (val T, ok bool) {
 	return
 }
 
-// Clear empties the heap.
+// Clear empties the heap and releases its backing slice. Use Reset to keep the
+// backing slice for reuse.
 func Clear[T any, MOM MinOrMax](heap *Heap[T, MOM]) {
 	heap.sl = nil
 }
@@ -174,7 +236,7 @@ func Clear[T any, MOM MinOrMax](heap *Heap[T, MOM]) {
 func Copy[T any, MOM MinOrMax](heap *Heap[T, MOM]) Heap[T, MOM] {
 	a := make([]T, len(heap.sl))
 	copy(a, heap.sl)
-	return Heap[T, MOM]{sl: a}
+	return Heap[T, MOM]{sl: a, policy: heap.policy}
 }
 
 // A BreakOrContinue value can be returned by an iteration callback to indicate
@@ -203,6 +265,7 @@ func FilterOrderable[T Orderable[T], MOM MinOrMax](heap *Heap[T, MOM], f func(*T
 }
 
 func filter[T any, MOM MinOrMax](heap *Heap[T, MOM], f func(*T) (bool, BreakOrContinue), cmp func(int, int) int) {
+	n := len(heap.sl)
 	i := 0
 	first := -1
 	for j := 0; j < len(heap.sl); j++ {
@@ -230,7 +293,9 @@ func filter[T any, MOM MinOrMax](heap *Heap[T, MOM], f func(*T) (bool, BreakOrCo
 		}
 	}
 
-	heap.sl = compact(heap.sl)
+	// zero the vacated slots (as for shrink)
+	clear(heap.sl[i:n])
+	heap.sl = compactWith(heap.sl, heap.policy)
 }
 
 // FromSlice initializes the a heap from the elements of a slice using Floyd's
@@ -307,16 +372,41 @@ func fromSlice[T any, MOM MinOrMax](heap *Heap[T, MOM], slice []T, cmp func(int,
 }
 
 func shrink[T any](a []T) []T {
+	return shrinkWith(a, ShrinkHalf)
+}
+
+func shrinkWith[T any](a []T, policy ShrinkPolicy) []T {
+	// zero the vacated slot so that it doesn't keep the removed element
+	// reachable if the backing array is kept
+	var zero T
+	a[len(a)-1] = zero
 	a = a[0 : len(a)-1]
-	return compact(a)
+	return compactWith(a, policy)
 }
 
 func compact[T any](a []T) []T {
+	return compactWith(a, ShrinkHalf)
+}
+
+func compactWith[T any](a []T, policy ShrinkPolicy) []T {
+	if policy == ShrinkNever {
+		return a
+	}
 	if len(a) == 0 {
 		// when the heap becomes empty again, ensure that it reverts to a nil
 		// backing slice without an associated heap allocation
 		return nil
 	}
+	if policy == ShrinkQuarter {
+		// leave room to grow so that the heap must double in size before it is
+		// reallocated again
+		if cap(a)/4 >= len(a) {
+			na := make([]T, len(a), 2*len(a))
+			copy(na, a)
+			return na
+		}
+		return a
+	}
 	if cap(a)/2 >= len(a) {
 		na := make([]T, len(a))
 		copy(na, a)
@@ -3,6 +3,7 @@ package heap
 import (
 	"fmt"
 	"math/rand"
+	"slices"
 	"sort"
 	"strings"
 	"testing"
@@ -641,6 +642,143 @@ func BenchmarkFromSliceVsPushElemsInSequencePathological(b *testing.B) {
 	}
 }
 
+func TestPushPop(t *testing.T) {
+	var heap Heap[int, Min]
+	if v := PushPop(&heap, 5); v != 5 || Len(&heap) != 0 {
+		t.Errorf("Expected PushPop on an empty heap to return its argument, got %v\n", v)
+	}
+	for _, elem := range []int{3, 8, 6} {
+		Push(&heap, elem)
+	}
+	if v := PushPop(&heap, 1); v != 1 {
+		t.Errorf("Expected 1, got %v\n", v)
+	}
+	if v := PushPop(&heap, 3); v != 3 {
+		t.Errorf("Expected 3, got %v\n", v)
+	}
+	if v := PushPop(&heap, 7); v != 3 {
+		t.Errorf("Expected 3, got %v\n", v)
+	}
+	if !slicesHaveSameElems(heap.sl, []int{6, 7, 8}) || !checkMinHeapProperty(&heap, 0) {
+		t.Errorf("Unexpected heap contents:\n%v\n", debugPrintHeap(&heap))
+	}
+}
+
+func TestReplace(t *testing.T) {
+	var heap Heap[int, Max]
+	if v, ok := Replace(&heap, 5); ok || v != 0 || Len(&heap) != 1 {
+		t.Errorf("Expected (0,false) and one element, got (%v,%v) and %v\n", v, ok, Len(&heap))
+	}
+	for _, elem := range []int{3, 8, 6} {
+		Push(&heap, elem)
+	}
+	// Unlike PushPop, Replace removes the root even if elem is greater.
+	if v, ok := Replace(&heap, 10); !ok || v != 8 {
+		t.Errorf("Expected (8,true), got (%v,%v)\n", v, ok)
+	}
+	if v, ok := Replace(&heap, 1); !ok || v != 10 {
+		t.Errorf("Expected (10,true), got (%v,%v)\n", v, ok)
+	}
+	if !slicesHaveSameElems(heap.sl, []int{1, 3, 5, 6}) || !checkMaxHeapProperty(&heap, 0) {
+		t.Errorf("Unexpected heap contents:\n%v\n", debugPrintHeap(&heap))
+	}
+}
+
+func TestPushPopAndReplaceOrderable(t *testing.T) {
+	var heap Heap[myCustomType, Min]
+	PushOrderable(&heap, myCustomType{Key: 2, Content: "two"})
+	PushOrderable(&heap, myCustomType{Key: 4, Content: "four"})
+	if v := PushPopOrderable(&heap, myCustomType{Key: 3, Content: "three"}); v.Content != "two" {
+		t.Errorf("Unexpected PushPopOrderable result: %v\n", v)
+	}
+	if v, ok := ReplaceOrderable(&heap, myCustomType{Key: 5, Content: "five"}); !ok || v.Content != "three" {
+		t.Errorf("Unexpected ReplaceOrderable result: (%v,%v)\n", v, ok)
+	}
+	if v, _ := Peek(&heap); v.Content != "four" {
+		t.Errorf("Unexpected root: %v\n", v)
+	}
+}
+
+func TestPushPopAndReplaceFuzz(t *testing.T) {
+	src := rand.NewSource(123)
+
+	var realHeap Heap[int, Min]
+	var naiveHeap []int
+	for i := 0; i < 10000; i++ {
+		rnd := src.Int63()
+		v := int(rnd % 100)
+		switch {
+		case rnd%3 == 0:
+			naiveMinHeapPush(&naiveHeap, v)
+			v1, _ := naiveHeapPop(&naiveHeap)
+			if v2 := PushPop(&realHeap, v); v1 != v2 {
+				t.Fatalf("PushPop: expected %v, got %v\n", v1, v2)
+			}
+		case rnd%3 == 1 && len(naiveHeap) > 0:
+			v1, _ := naiveHeapPop(&naiveHeap)
+			naiveMinHeapPush(&naiveHeap, v)
+			if v2, _ := Replace(&realHeap, v); v1 != v2 {
+				t.Fatalf("Replace: expected %v, got %v\n", v1, v2)
+			}
+		default:
+			naiveMinHeapPush(&naiveHeap, v)
+			Push(&realHeap, v)
+		}
+
+		if !checkMinHeapProperty(&realHeap, 0) {
+			t.Fatalf("Real heap does not have min heap property:\n%v\n", debugPrintHeap(&realHeap))
+		}
+		if !slicesHaveSameElems(naiveHeap, realHeap.sl) {
+			t.Fatalf("Elements not the same:\n%+v\n\n%v\n", naiveHeap, debugPrintHeap(&realHeap))
+		}
+	}
+}
+
+// Compares adding a batch of elements to a heap of 10000 elements by pushing
+// each element, by rebuilding the heap, and by PushAll (which should pick the
+// faster of the two). Run with -bench PushAllCrossover to see where the
+// crossover is.
+func BenchmarkPushAllCrossover(b *testing.B) {
+	const n = 10000
+	for _, pathological := range []bool{false, true} {
+		for _, k := range []int{100, 1000, 2000, 5000, 10000, 100000} {
+			var base Heap[int, Min]
+			batch := make([]int, k)
+			for i := range n {
+				Push(&base, i%100)
+			}
+			for i := range batch {
+				if pathological {
+					batch[i] = -i
+				} else {
+					batch[i] = i % 100
+				}
+			}
+			name := fmt.Sprintf("pathological=%v/k=%v", pathological, k)
+			b.Run(name+"/Push", func(b *testing.B) {
+				for range b.N {
+					h := Heap[int, Min]{sl: slices.Clone(base.sl)}
+					for _, elem := range batch {
+						Push(&h, elem)
+					}
+				}
+			})
+			b.Run(name+"/Rebuild", func(b *testing.B) {
+				for range b.N {
+					h := Heap[int, Min]{sl: slices.Clone(base.sl)}
+					FromSlice(&h, append(h.sl, batch...))
+				}
+			})
+			b.Run(name+"/PushAll", func(b *testing.B) {
+				for range b.N {
+					h := Heap[int, Min]{sl: slices.Clone(base.sl)}
+					PushAll(&h, batch)
+				}
+			})
+		}
+	}
+}
+
 // Fuzz tests a randomly generated sequence of operations against the same set
 // of operations performed on a sorted slice.
 func TestMinHeapFuzz(t *testing.T) {
@@ -849,3 +987,67 @@ func benchmarkPop(b *testing.B, nElements int) {
 		}
 	}
 }
+
+// Compare the binary Heap against DaryHeaps of arity 2, 4 and 8 for a large
+// queue with a mix of pushes and pops.
+func BenchmarkArityHeap(b *testing.B) {
+	benchmarkArity(b, func(elems []int) {
+		var h Heap[int, Min]
+		for _, e := range elems {
+			Push(&h, e)
+		}
+		for range elems {
+			Pop(&h)
+		}
+	})
+}
+
+func BenchmarkArity2(b *testing.B) {
+	benchmarkArity(b, func(elems []int) {
+		var h DaryHeap[int, Min, Arity2]
+		for _, e := range elems {
+			PushDary(&h, e)
+		}
+		for range elems {
+			PopDary(&h)
+		}
+	})
+}
+
+func BenchmarkArity4(b *testing.B) {
+	benchmarkArity(b, func(elems []int) {
+		var h DaryHeap[int, Min, Arity4]
+		for _, e := range elems {
+			PushDary(&h, e)
+		}
+		for range elems {
+			PopDary(&h)
+		}
+	})
+}
+
+func BenchmarkArity8(b *testing.B) {
+	benchmarkArity(b, func(elems []int) {
+		var h DaryHeap[int, Min, Arity8]
+		for _, e := range elems {
+			PushDary(&h, e)
+		}
+		for range elems {
+			PopDary(&h)
+		}
+	})
+}
+
+func benchmarkArity(b *testing.B, pushThenPopAll func(elems []int)) {
+	src := rand.NewSource(456)
+	elems := make([]int, 100000)
+	for i := range elems {
+		elems[i] = int(src.Int63())
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		pushThenPopAll(elems)
+	}
+}
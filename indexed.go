@@ -0,0 +1,253 @@
+package heap
+
+import (
+	"github.com/savsgio/gotils/nocopy"
+	c "golang.org/x/exp/constraints"
+)
+
+// IndexedHeap is a min or max heap whose push functions return a Handle for
+// the pushed element. The handle remains valid as the element moves around
+// within the heap, so that the element can later be updated or removed in
+// O(log n) time. This makes IndexedHeap suitable for algorithms that require
+// a decrease-key operation (e.g. Dijkstra's and Prim's algorithms). The
+// default value of IndexedHeap is a valid empty heap.
+type IndexedHeap[T any, MOM MinOrMax] struct {
+	sl []*indexedEntry[T]
+	nocopy.NoCopy
+}
+
+type indexedEntry[T any] struct {
+	val   T
+	index int
+}
+
+// A Handle refers to an element of an IndexedHeap. A handle is valid until
+// the element it refers to is popped or removed from the heap. The zero value
+// of Handle is never valid.
+type Handle[T any] struct {
+	e *indexedEntry[T]
+}
+
+// LenIndexed returns the number of elements in the heap.
+func LenIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM]) int {
+	return len(heap.sl)
+}
+
+// PushIndexed adds an element to the heap for a T that satisfies
+// constraints.Ordered and returns a handle to the element.
+func PushIndexed[T c.Ordered, MOM MinOrMax](heap *IndexedHeap[T, MOM], elem T) Handle[T] {
+	return pushIndexed(heap, elem, func(i, j int) int { return cmpOrdered(heap.sl[i].val, heap.sl[j].val) })
+}
+
+// PushIndexedOrderable adds an element to the heap for a T that implements
+// Orderable and returns a handle to the element.
+func PushIndexedOrderable[T Orderable[T], MOM MinOrMax](heap *IndexedHeap[T, MOM], elem T) Handle[T] {
+	return pushIndexed(heap, elem, func(i, j int) int {
+		return heap.sl[i].val.Cmp(heap.sl[j].val)
+	})
+}
+
+func pushIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM], elem T, cmp func(i, j int) int) Handle[T] {
+	e := &indexedEntry[T]{val: elem, index: len(heap.sl)}
+	heap.sl = append(heap.sl, e)
+	bubbleIndexed(heap, e.index, cmp)
+	return Handle[T]{e}
+}
+
+// PopIndexed removes the min/max element from the heap for a T that
+// satisfies constraints.Ordered. Any handle to the element becomes invalid.
+func PopIndexed[T c.Ordered, MOM MinOrMax](heap *IndexedHeap[T, MOM]) (T, bool) {
+	return popIndexed(heap, func(i, j int) int { return cmpOrdered(heap.sl[i].val, heap.sl[j].val) })
+}
+
+// PopIndexedOrderable removes the min/max element from the heap for a T that
+// implements Orderable. Any handle to the element becomes invalid.
+func PopIndexedOrderable[T Orderable[T], MOM MinOrMax](heap *IndexedHeap[T, MOM]) (T, bool) {
+	return popIndexed(heap, func(i, j int) int {
+		return heap.sl[i].val.Cmp(heap.sl[j].val)
+	})
+}
+
+func popIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM], cmp func(i, j int) int) (val T, ok bool) {
+	if len(heap.sl) == 0 {
+		return
+	}
+	return removeIndexedAt(heap, 0, cmp), true
+}
+
+// PeekIndexed returns the min/max element from the heap without removing it.
+func PeekIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM]) (val T, ok bool) {
+	if len(heap.sl) == 0 {
+		return
+	}
+	ok = true
+	val = heap.sl[0].val
+	return
+}
+
+// ContainsIndexed returns true if the handle refers to an element that is
+// currently in the heap.
+func ContainsIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM], h Handle[T]) bool {
+	return h.e != nil && h.e.index >= 0 && h.e.index < len(heap.sl) && heap.sl[h.e.index] == h.e
+}
+
+// GetIndexed returns the value of the element referred to by the handle. The
+// second return value is false if the handle is not valid for the heap.
+func GetIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM], h Handle[T]) (val T, ok bool) {
+	if !ContainsIndexed(heap, h) {
+		return
+	}
+	ok = true
+	val = h.e.val
+	return
+}
+
+// UpdateIndexed replaces the value of the element referred to by the handle
+// and restores the heap property for a T that satisfies constraints.Ordered.
+// The value may move in either direction (i.e. this function implements both
+// decrease-key and increase-key). Returns false if the handle is not valid for
+// the heap.
+func UpdateIndexed[T c.Ordered, MOM MinOrMax](heap *IndexedHeap[T, MOM], h Handle[T], val T) bool {
+	return updateIndexed(heap, h, val, func(i, j int) int { return cmpOrdered(heap.sl[i].val, heap.sl[j].val) })
+}
+
+// As for UpdateIndexed, but for the case where T cannot be compared using <
+// and there is an implementation of Orderable[T].
+func UpdateIndexedOrderable[T Orderable[T], MOM MinOrMax](heap *IndexedHeap[T, MOM], h Handle[T], val T) bool {
+	return updateIndexed(heap, h, val, func(i, j int) int {
+		return heap.sl[i].val.Cmp(heap.sl[j].val)
+	})
+}
+
+func updateIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM], h Handle[T], val T, cmp func(i, j int) int) bool {
+	if !ContainsIndexed(heap, h) {
+		return false
+	}
+	h.e.val = val
+	i := bubbleIndexed(heap, h.e.index, cmp)
+	siftDownIndexed(heap, i, cmp)
+	return true
+}
+
+// RemoveIndexed removes the element referred to by the handle from the heap
+// for a T that satisfies constraints.Ordered. The second return value is
+// false if the handle is not valid for the heap.
+func RemoveIndexed[T c.Ordered, MOM MinOrMax](heap *IndexedHeap[T, MOM], h Handle[T]) (T, bool) {
+	return removeIndexed(heap, h, func(i, j int) int { return cmpOrdered(heap.sl[i].val, heap.sl[j].val) })
+}
+
+// As for RemoveIndexed, but for the case where T cannot be compared using <
+// and there is an implementation of Orderable[T].
+func RemoveIndexedOrderable[T Orderable[T], MOM MinOrMax](heap *IndexedHeap[T, MOM], h Handle[T]) (T, bool) {
+	return removeIndexed(heap, h, func(i, j int) int {
+		return heap.sl[i].val.Cmp(heap.sl[j].val)
+	})
+}
+
+func removeIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM], h Handle[T], cmp func(i, j int) int) (val T, ok bool) {
+	if !ContainsIndexed(heap, h) {
+		return
+	}
+	return removeIndexedAt(heap, h.e.index, cmp), true
+}
+
+// ClearIndexed empties the heap. All handles to elements of the heap become
+// invalid.
+func ClearIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM]) {
+	var zero T
+	for _, e := range heap.sl {
+		e.index = -1
+		// don't keep the element reachable from stale handles
+		e.val = zero
+	}
+	heap.sl = nil
+}
+
+func removeIndexedAt[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM], i int, cmp func(i, j int) int) T {
+	// The hole technique used by pop works for any starting index, not just the
+	// root: every element moved up into the hole is no better than the element
+	// originally at i, and hence no better than the parent of i.
+	e := heap.sl[i]
+	e.index = -1
+	val := e.val
+	// don't keep the element reachable from stale handles
+	var zero T
+	e.val = zero
+
+	i = pushHoleDownToLeafIndexed(heap, i, cmp)
+
+	if i+1 == len(heap.sl) {
+		heap.sl = shrink(heap.sl)
+		return val
+	}
+
+	displaced := heap.sl[len(heap.sl)-1]
+	heap.sl = shrink(heap.sl)
+	heap.sl[i] = displaced
+	displaced.index = i
+	bubbleIndexed(heap, i, cmp)
+
+	return val
+}
+
+func pushHoleDownToLeafIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM], i int, cmp func(i, j int) int) int {
+	var mom MOM
+
+	for {
+		lci := leftChildIndex(i)
+		rci := rightChildIndex(i)
+		if lci >= len(heap.sl) {
+			break
+		}
+
+		if rci >= len(heap.sl) || mom.mul()*cmp(rci, lci) > 0 {
+			heap.sl[i] = heap.sl[lci]
+			heap.sl[i].index = i
+			i = lci
+		} else {
+			heap.sl[i] = heap.sl[rci]
+			heap.sl[i].index = i
+			i = rci
+		}
+	}
+	return i
+}
+
+func bubbleIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM], i int, cmp func(i, j int) int) int {
+	var mom MOM
+
+	for i > 0 {
+		pi := parentIndex(i)
+		if mom.mul()*cmp(i, pi) >= 0 {
+			break
+		}
+		swapIndexed(heap, i, pi)
+		i = pi
+	}
+	return i
+}
+
+func siftDownIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM], i int, cmp func(i, j int) int) {
+	var mom MOM
+
+	for {
+		ci := leftChildIndex(i)
+		if ci >= len(heap.sl) {
+			break
+		}
+		if rci := rightChildIndex(i); rci < len(heap.sl) && mom.mul()*cmp(rci, ci) < 0 {
+			ci = rci
+		}
+		if mom.mul()*cmp(ci, i) >= 0 {
+			break
+		}
+		swapIndexed(heap, i, ci)
+		i = ci
+	}
+}
+
+func swapIndexed[T any, MOM MinOrMax](heap *IndexedHeap[T, MOM], i, j int) {
+	heap.sl[i], heap.sl[j] = heap.sl[j], heap.sl[i]
+	heap.sl[i].index = i
+	heap.sl[j].index = j
+}
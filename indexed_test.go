@@ -0,0 +1,233 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestIndexedPushAndPop(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14}
+	var heap IndexedHeap[int, Min]
+	for _, elem := range elems {
+		PushIndexed(&heap, elem)
+	}
+	sort.Ints(elems)
+	for i := 0; i < len(elems); i++ {
+		v, ok := PopIndexed(&heap)
+		if !ok {
+			t.Errorf("Expecting ok")
+		}
+		if v != elems[i] {
+			t.Errorf("Unexpected value")
+		}
+	}
+	if heap.sl != nil {
+		t.Errorf("Expecting empty heap to have nil backing slice")
+	}
+}
+
+func TestIndexedUpdate(t *testing.T) {
+	var heap IndexedHeap[int, Min]
+	handles := make([]Handle[int], 10)
+	for i := range handles {
+		handles[i] = PushIndexed(&heap, i*10)
+	}
+
+	// decrease-key
+	if !UpdateIndexed(&heap, handles[7], -1) {
+		t.Fatalf("Expecting update to succeed")
+	}
+	if v, _ := PeekIndexed(&heap); v != -1 {
+		t.Errorf("Expected -1 at root, got %v\n", v)
+	}
+	// increase-key
+	if !UpdateIndexed(&heap, handles[7], 1000) {
+		t.Fatalf("Expecting update to succeed")
+	}
+	if v, _ := PeekIndexed(&heap); v != 0 {
+		t.Errorf("Expected 0 at root, got %v\n", v)
+	}
+	if !checkIndexedHeapProperty(&heap) {
+		t.Errorf("Min heap property violated")
+	}
+
+	for i := range handles {
+		v, ok := GetIndexed(&heap, handles[i])
+		if !ok {
+			t.Errorf("Expecting handle %v to be valid", i)
+		}
+		if i != 7 && v != i*10 {
+			t.Errorf("Unexpected value %v for handle %v\n", v, i)
+		}
+	}
+}
+
+func TestIndexedRemove(t *testing.T) {
+	var heap IndexedHeap[int, Max]
+	handles := make([]Handle[int], 10)
+	for i := range handles {
+		handles[i] = PushIndexed(&heap, i)
+	}
+
+	v, ok := RemoveIndexed(&heap, handles[4])
+	if !ok || v != 4 {
+		t.Errorf("Unexpected remove result: %v %v\n", v, ok)
+	}
+	if ContainsIndexed(&heap, handles[4]) {
+		t.Errorf("Removed handle should not be contained in heap")
+	}
+	if _, ok := RemoveIndexed(&heap, handles[4]); ok {
+		t.Errorf("Removing an invalid handle should return ok=false")
+	}
+	if UpdateIndexed(&heap, handles[4], 4) {
+		t.Errorf("Updating an invalid handle should return false")
+	}
+
+	expected := []int{9, 8, 7, 6, 5, 3, 2, 1, 0}
+	for _, e := range expected {
+		v, ok := PopIndexed(&heap)
+		if !ok || v != e {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", e, v, ok)
+		}
+	}
+	for i := range handles {
+		if ContainsIndexed(&heap, handles[i]) {
+			t.Errorf("Popped handle %v should not be contained in heap", i)
+		}
+	}
+}
+
+func TestIndexedHandleFromOtherHeap(t *testing.T) {
+	var heap1, heap2 IndexedHeap[int, Min]
+	h := PushIndexed(&heap1, 1)
+	PushIndexed(&heap2, 1)
+	if ContainsIndexed(&heap2, h) {
+		t.Errorf("Handle should not be valid for a different heap")
+	}
+	if ContainsIndexed(&heap1, Handle[int]{}) {
+		t.Errorf("Zero handle should never be valid")
+	}
+}
+
+func TestIndexedOrderable(t *testing.T) {
+	var heap IndexedHeap[myCustomType, Min]
+	h := PushIndexedOrderable(&heap, myCustomType{Key: 5, Content: "foo"})
+	PushIndexedOrderable(&heap, myCustomType{Key: 3, Content: "bar"})
+	UpdateIndexedOrderable(&heap, h, myCustomType{Key: 1, Content: "foo"})
+	v, ok := PopIndexedOrderable(&heap)
+	if !ok || v.Content != "foo" {
+		t.Errorf("Unexpected pop result: %v %v\n", v, ok)
+	}
+}
+
+// Fuzz tests a randomly generated sequence of operations against the same set
+// of operations performed on a sorted slice.
+func TestIndexedHeapFuzz(t *testing.T) {
+	src := rand.NewSource(123)
+
+	var realHeap IndexedHeap[int, Min]
+	var naiveHeap []int
+	var handles []Handle[int]
+
+	for i := 0; i < 10000; i++ {
+		rnd := src.Int63()
+		if rnd%13 == 0 {
+			v1, ok1 := naiveHeapPop(&naiveHeap)
+			v2, ok2 := PopIndexed(&realHeap)
+			if v1 != v2 || ok1 != ok2 {
+				t.Fatalf("Got %v,%v, expected %v,%v\n", v2, ok2, v1, ok1)
+			}
+		} else if rnd%7 == 0 && len(handles) > 0 {
+			h := handles[int(rnd/7)%len(handles)]
+			old, ok := GetIndexed(&realHeap, h)
+			if !ok {
+				continue
+			}
+			v := int((rnd / 91) % 100)
+			naiveHeapRemove(&naiveHeap, old)
+			if rnd%2 == 0 {
+				naiveMinHeapPush(&naiveHeap, v)
+				UpdateIndexed(&realHeap, h, v)
+			} else {
+				RemoveIndexed(&realHeap, h)
+			}
+		} else {
+			v := int(rnd % 100)
+			naiveMinHeapPush(&naiveHeap, v)
+			handles = append(handles, PushIndexed(&realHeap, v))
+		}
+
+		if !checkIndexedHeapProperty(&realHeap) {
+			t.Fatalf("Real heap does not have min heap property")
+		}
+	}
+
+	for {
+		v1, ok1 := PopIndexed(&realHeap)
+		v2, ok2 := naiveHeapPop(&naiveHeap)
+
+		if v1 != v2 || ok1 != ok2 {
+			t.Errorf("Oh no! Got %v,%v, expected %v,%v.\n", v1, ok1, v2, ok2)
+			break
+		}
+
+		if !ok1 {
+			break
+		}
+	}
+}
+
+func TestIndexedDijkstra(t *testing.T) {
+	type edge struct{ to, weight int }
+	graph := [][]edge{
+		{{1, 7}, {2, 9}, {5, 14}},
+		{{0, 7}, {2, 10}, {3, 15}},
+		{{0, 9}, {1, 10}, {3, 11}, {5, 2}},
+		{{1, 15}, {2, 11}, {4, 6}},
+		{{3, 6}, {5, 9}},
+		{{0, 14}, {2, 2}, {4, 9}},
+	}
+
+	var heap IndexedHeap[distTo, Min]
+	dist := make([]int, len(graph))
+	handles := make([]Handle[distTo], len(graph))
+	for i := range graph {
+		dist[i] = -1
+	}
+	dist[0] = 0
+	handles[0] = PushIndexedOrderable(&heap, distTo{0, 0})
+	for {
+		v, ok := PopIndexedOrderable(&heap)
+		if !ok {
+			break
+		}
+		for _, e := range graph[v.node] {
+			d := v.dist + e.weight
+			if dist[e.to] == -1 {
+				dist[e.to] = d
+				handles[e.to] = PushIndexedOrderable(&heap, distTo{e.to, d})
+			} else if d < dist[e.to] && ContainsIndexed(&heap, handles[e.to]) {
+				dist[e.to] = d
+				UpdateIndexedOrderable(&heap, handles[e.to], distTo{e.to, d})
+			}
+		}
+	}
+
+	expected := []int{0, 7, 9, 20, 20, 11}
+	for i := range expected {
+		if dist[i] != expected[i] {
+			t.Errorf("Unexpected distances: %v\n", dist)
+			break
+		}
+	}
+}
+
+type distTo struct {
+	node int
+	dist int
+}
+
+func (a distTo) Cmp(b distTo) int {
+	return a.dist - b.dist
+}
@@ -0,0 +1,75 @@
+package heap
+
+import (
+	"iter"
+
+	c "golang.org/x/exp/constraints"
+)
+
+// All returns an iterator over the elements of the heap in the order given by
+// the underlying slice. The heap must not be modified during iteration.
+func All[T any, MOM MinOrMax](heap *Heap[T, MOM]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, elem := range heap.sl {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+// Drain returns an iterator that pops elements from the heap in min/max order
+// for a T that satisfies constraints.Ordered. Each element is removed from the
+// heap before it is yielded, so if iteration stops early then the remaining
+// elements stay in the heap.
+func Drain[T c.Ordered, MOM MinOrMax](heap *Heap[T, MOM]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			elem, ok := Pop(heap)
+			if !ok || !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+// As for Drain, but for the case where T cannot be compared using < and there
+// is an implementation of Orderable[T].
+func DrainOrderable[T Orderable[T], MOM MinOrMax](heap *Heap[T, MOM]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			elem, ok := PopOrderable(heap)
+			if !ok || !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns an iterator over the elements of the heap in min/max order
+// for a T that satisfies constraints.Ordered. The iterator works on a copy of
+// the heap, which is taken when iteration begins, so the heap itself is left
+// unchanged.
+func Sorted[T c.Ordered, MOM MinOrMax](heap *Heap[T, MOM]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		cp := Copy(heap)
+		for elem := range Drain(&cp) {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+// As for Sorted, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func SortedOrderable[T Orderable[T], MOM MinOrMax](heap *Heap[T, MOM]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		cp := Copy(heap)
+		for elem := range DrainOrderable(&cp) {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package heap
+
+import (
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14}
+	var heap Heap[int, Min]
+	for _, elem := range elems {
+		Push(&heap, elem)
+	}
+	all := slices.Collect(All(&heap))
+	if !slices.Equal(all, heap.sl) {
+		t.Errorf("Expected All to yield elements in slice order: %v %v\n", all, heap.sl)
+	}
+	if Len(&heap) != len(elems) {
+		t.Errorf("All should not remove elements from the heap")
+	}
+}
+
+func TestAllBreak(t *testing.T) {
+	var heap Heap[int, Min]
+	for i := 0; i < 10; i++ {
+		Push(&heap, i)
+	}
+	n := 0
+	for range All(&heap) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("Expected iteration to stop after 3 elements, got %v\n", n)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14}
+	var heap Heap[int, Max]
+	for _, elem := range elems {
+		Push(&heap, elem)
+	}
+	drained := slices.Collect(Drain(&heap))
+	sort.Sort(sort.Reverse(sort.IntSlice(elems)))
+	if !slices.Equal(drained, elems) {
+		t.Errorf("Unexpected drain order: %v\n", drained)
+	}
+	if heap.sl != nil {
+		t.Errorf("Expecting drained heap to have nil backing slice")
+	}
+}
+
+func TestDrainBreak(t *testing.T) {
+	var heap Heap[int, Min]
+	for i := 0; i < 10; i++ {
+		Push(&heap, i)
+	}
+	for v := range Drain(&heap) {
+		if v == 4 {
+			break
+		}
+	}
+	if Len(&heap) != 5 {
+		t.Errorf("Expected 5 elements to remain in heap, got %v\n", Len(&heap))
+	}
+	if v, _ := Peek(&heap); v != 5 {
+		t.Errorf("Expected 5 at root, got %v\n", v)
+	}
+}
+
+func TestDrainOrderable(t *testing.T) {
+	var heap Heap[myCustomType, Min]
+	PushOrderable(&heap, myCustomType{Key: 3, Content: "c"})
+	PushOrderable(&heap, myCustomType{Key: 1, Content: "a"})
+	PushOrderable(&heap, myCustomType{Key: 2, Content: "b"})
+	var s string
+	for v := range DrainOrderable(&heap) {
+		s += v.Content
+	}
+	if s != "abc" {
+		t.Errorf("Unexpected drain order: %v\n", s)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14}
+	var heap Heap[int, Min]
+	for _, elem := range elems {
+		Push(&heap, elem)
+	}
+	before := slices.Clone(heap.sl)
+	sorted := slices.Collect(Sorted(&heap))
+	sort.Ints(elems)
+	if !slices.Equal(sorted, elems) {
+		t.Errorf("Unexpected sorted order: %v\n", sorted)
+	}
+	if !slices.Equal(before, heap.sl) {
+		t.Errorf("Sorted should not modify the heap")
+	}
+}
+
+func TestSortedOrderable(t *testing.T) {
+	var heap Heap[myCustomType, Max]
+	PushOrderable(&heap, myCustomType{Key: 3, Content: "c"})
+	PushOrderable(&heap, myCustomType{Key: 1, Content: "a"})
+	PushOrderable(&heap, myCustomType{Key: 2, Content: "b"})
+	var s string
+	for v := range SortedOrderable(&heap) {
+		s += v.Content
+	}
+	if s != "cba" {
+		t.Errorf("Unexpected sorted order: %v\n", s)
+	}
+	if Len(&heap) != 3 {
+		t.Errorf("Sorted should not modify the heap")
+	}
+}
@@ -0,0 +1,65 @@
+package heap
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// MarshalJSON implements json.Marshaler. The heap is encoded as a JSON array of
+// its elements in the order given by the underlying slice. Use SortedJSON to
+// encode the elements in min/max order instead.
+func (heap *Heap[T, MOM]) MarshalJSON() ([]byte, error) {
+	if heap.sl == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(heap.sl)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a JSON array of
+// elements in any order (or null for an empty heap). The previous contents of
+// the heap (if any) are discarded and the elements are rebuilt into a heap
+// using Floyd's algorithm (as for FromSlice). T must either satisfy
+// constraints.Ordered or implement Orderable.
+func (heap *Heap[T, MOM]) UnmarshalJSON(data []byte) error {
+	cmp, err := runtimeCmp[T]()
+	if err != nil {
+		return err
+	}
+
+	var sl []T
+	if err := json.Unmarshal(data, &sl); err != nil {
+		return err
+	}
+	fromSlice(heap, sl, func(i, j int) int { return cmp(heap.sl[i], heap.sl[j]) })
+	return nil
+}
+
+// SortedJSON returns a json.Marshaler that encodes the heap as a JSON array of
+// its elements in min/max order (i.e. the order in which they would be popped).
+// The heap itself is not modified. T must either satisfy constraints.Ordered or
+// implement Orderable.
+//
+//	json.Marshal(map[string]any{"queue": heap.SortedJSON(&h)})
+func SortedJSON[T any, MOM MinOrMax](heap *Heap[T, MOM]) json.Marshaler {
+	return sortedJSON[T, MOM]{heap}
+}
+
+type sortedJSON[T any, MOM MinOrMax] struct {
+	heap *Heap[T, MOM]
+}
+
+func (s sortedJSON[T, MOM]) MarshalJSON() ([]byte, error) {
+	var mom MOM
+
+	cmp, err := runtimeCmp[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	sl := slices.Clone(s.heap.sl)
+	if sl == nil {
+		sl = []T{}
+	}
+	slices.SortFunc(sl, func(a, b T) int { return mom.mul() * cmp(a, b) })
+	return json.Marshal(sl)
+}
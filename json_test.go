@@ -0,0 +1,110 @@
+package heap
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14}
+	var heap Heap[int, Min]
+	for _, elem := range elems {
+		Push(&heap, elem)
+	}
+	data, err := json.Marshal(&heap)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if string(data) != "[-3,1,2,9,5,17,18,19,14]" {
+		t.Errorf("Unexpected JSON: %s\n", data)
+	}
+	var decoded Heap[int, Min]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if !slices.Equal(decoded.sl, heap.sl) {
+		t.Errorf("Expected decoded heap to have the same layout: %v %v\n", decoded.sl, heap.sl)
+	}
+}
+
+func TestJSONEmpty(t *testing.T) {
+	var heap Heap[int, Min]
+	data, err := json.Marshal(&heap)
+	if err != nil || string(data) != "[]" {
+		t.Errorf("Unexpected result: %s %v\n", data, err)
+	}
+	if err := json.Unmarshal([]byte("null"), &heap); err != nil || heap.sl != nil {
+		t.Errorf("Unexpected result: %v %v\n", heap.sl, err)
+	}
+	if err := json.Unmarshal([]byte("[]"), &heap); err != nil || heap.sl != nil {
+		t.Errorf("Unexpected result: %v %v\n", heap.sl, err)
+	}
+}
+
+func TestJSONUnmarshalAnyOrder(t *testing.T) {
+	var heap Heap[int, Max]
+	if err := json.Unmarshal([]byte("[1,2,6,7,3,2,4,5,6,7,9,9,10,-1,-3,-2,15,99,100,75]"), &heap); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if !checkMaxHeapProperty(&heap, 0) {
+		t.Errorf("Max heap property violated: %v\n", heap.sl)
+	}
+	if err := json.Unmarshal([]byte(`{"a": 1}`), &heap); err == nil {
+		t.Errorf("Expected error for invalid JSON")
+	}
+}
+
+func TestJSONOrderable(t *testing.T) {
+	var heap Heap[myCustomType, Max]
+	if err := json.Unmarshal([]byte(`[{"Key":1,"Content":"a"},{"Key":3,"Content":"c"},{"Key":2,"Content":"b"}]`), &heap); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	data, err := json.Marshal(SortedJSON(&heap))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if string(data) != `[{"Key":3,"Content":"c"},{"Key":2,"Content":"b"},{"Key":1,"Content":"a"}]` {
+		t.Errorf("Unexpected JSON: %s\n", data)
+	}
+}
+
+func TestSortedJSON(t *testing.T) {
+	type debugInfo struct {
+		Queue json.Marshaler `json:"queue"`
+	}
+	var heap Heap[int, Min]
+	FromSlice(&heap, []int{5, 3, 8, 1, 9, 2})
+	before := slices.Clone(heap.sl)
+	data, err := json.Marshal(debugInfo{SortedJSON(&heap)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if string(data) != `{"queue":[1,2,3,5,8,9]}` {
+		t.Errorf("Unexpected JSON: %s\n", data)
+	}
+	if !slices.Equal(before, heap.sl) {
+		t.Errorf("SortedJSON should not modify the heap")
+	}
+}
+
+func TestJSONInStruct(t *testing.T) {
+	type config struct {
+		Name  string
+		Queue Heap[string, Min]
+	}
+	var cfg config
+	if err := json.Unmarshal([]byte(`{"Name":"q","Queue":["c","a","b"]}`), &cfg); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if v, _ := Peek(&cfg.Queue); v != "a" {
+		t.Errorf("Expected a at root, got %v\n", v)
+	}
+	data, err := json.Marshal(&cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if string(data) != `{"Name":"q","Queue":["a","c","b"]}` {
+		t.Errorf("Unexpected JSON: %s\n", data)
+	}
+}
@@ -0,0 +1,79 @@
+package heap
+
+import (
+	"iter"
+
+	c "golang.org/x/exp/constraints"
+)
+
+// Merge returns an iterator that lazily merges sequences that are each sorted
+// in ascending order into a single ascending sequence, for a T that satisfies
+// constraints.Ordered. Elements that compare equal are yielded in the order of
+// the sequences that they come from. Each input sequence is consumed at most
+// once, and only as far as is needed to produce the elements that are yielded.
+func Merge[T c.Ordered](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return merge(seqs, cmpOrdered[T], false)
+}
+
+// As for Merge, but for the case where T cannot be compared using < and there
+// is an implementation of Orderable[T].
+func MergeOrderable[T Orderable[T]](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return merge(seqs, T.Cmp, false)
+}
+
+// As for Merge, but only the first of each run of elements that compare equal
+// is yielded, so the output contains no duplicates.
+func MergeUnique[T c.Ordered](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return merge(seqs, cmpOrdered[T], true)
+}
+
+// As for MergeUnique, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func MergeUniqueOrderable[T Orderable[T]](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return merge(seqs, T.Cmp, true)
+}
+
+type mergeCursor[T any] struct {
+	val  T
+	src  int
+	next func() (T, bool)
+}
+
+func merge[T any](seqs []iter.Seq[T], cmp func(a, b T) int, unique bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var heap Heap[mergeCursor[T], Min]
+		hcmp := func(i, j int) int {
+			if r := cmp(heap.sl[i].val, heap.sl[j].val); r != 0 {
+				return r
+			}
+			return heap.sl[i].src - heap.sl[j].src
+		}
+
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			if v, ok := next(); ok {
+				push(&heap, mergeCursor[T]{v, i, next}, hcmp)
+			}
+		}
+
+		var last T
+		first := true
+		for len(heap.sl) > 0 {
+			cur := heap.sl[0]
+			if !unique || first || cmp(last, cur.val) != 0 {
+				if !yield(cur.val) {
+					return
+				}
+				last = cur.val
+				first = false
+			}
+			if v, ok := cur.next(); ok {
+				cur.val = v
+				replace(&heap, cur, hcmp)
+			} else {
+				pop(&heap, hcmp)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package heap
+
+import (
+	"iter"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	a := []int{1, 4, 7, 10}
+	b := []int{2, 5, 8}
+	c := []int{0, 3, 6, 9, 11, 12}
+	merged := slices.Collect(Merge(slices.Values(a), slices.Values(b), slices.Values(c)))
+	expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	if !slices.Equal(merged, expected) {
+		t.Errorf("Unexpected merge result: %v\n", merged)
+	}
+}
+
+func TestMergeEmpty(t *testing.T) {
+	if merged := slices.Collect(Merge[int]()); len(merged) != 0 {
+		t.Errorf("Expected empty merge result, got %v\n", merged)
+	}
+	merged := slices.Collect(Merge(slices.Values([]int{}), slices.Values([]int{1}), slices.Values([]int(nil))))
+	if !slices.Equal(merged, []int{1}) {
+		t.Errorf("Unexpected merge result: %v\n", merged)
+	}
+}
+
+func TestMergeUnique(t *testing.T) {
+	a := []int{1, 1, 2, 4}
+	b := []int{1, 2, 3, 4, 4}
+	merged := slices.Collect(MergeUnique(slices.Values(a), slices.Values(b)))
+	if !slices.Equal(merged, []int{1, 2, 3, 4}) {
+		t.Errorf("Unexpected merge result: %v\n", merged)
+	}
+}
+
+func TestMergeOrderableStable(t *testing.T) {
+	a := []myCustomType{{1, "a1"}, {2, "a2"}}
+	b := []myCustomType{{1, "b1"}, {2, "b2"}}
+	var s string
+	for v := range MergeOrderable(slices.Values(a), slices.Values(b)) {
+		s += v.Content
+	}
+	if s != "a1b1a2b2" {
+		t.Errorf("Unexpected merge order: %v\n", s)
+	}
+	s = ""
+	for v := range MergeUniqueOrderable(slices.Values(a), slices.Values(b)) {
+		s += v.Content
+	}
+	if s != "a1a2" {
+		t.Errorf("Unexpected unique merge order: %v\n", s)
+	}
+}
+
+func TestMergeBreakStopsSources(t *testing.T) {
+	consumed := 0
+	infinite := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			consumed++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	for v := range Merge(infinite, slices.Values([]int{0, 1, 2})) {
+		if v >= 2 {
+			break
+		}
+	}
+	if consumed > 4 {
+		t.Errorf("Expected merge to consume its inputs lazily, consumed %v\n", consumed)
+	}
+}
+
+func TestMergeFuzz(t *testing.T) {
+	src := rand.NewSource(123)
+	var seqs [][]int
+	var all []int
+	for i := 0; i < 20; i++ {
+		var s []int
+		v := 0
+		for j := int(src.Int63() % 100); j > 0; j-- {
+			v += int(src.Int63() % 5)
+			s = append(s, v)
+		}
+		seqs = append(seqs, s)
+		all = append(all, s...)
+	}
+	slices.Sort(all)
+
+	var its []iter.Seq[int]
+	for _, s := range seqs {
+		its = append(its, slices.Values(s))
+	}
+	var merged []int
+	for v := range Merge(its...) {
+		merged = append(merged, v)
+	}
+	if !slices.Equal(merged, all) {
+		t.Errorf("Unexpected merge result")
+	}
+}
@@ -0,0 +1,245 @@
+package heap
+
+import (
+	"math/bits"
+
+	"github.com/savsgio/gotils/nocopy"
+	c "golang.org/x/exp/constraints"
+)
+
+// MinMaxHeap is a double-ended heap that gives O(1) access to both its min and
+// max elements and O(log n) removal of either. It is backed by a slice denoting
+// an implicit min-max heap (Atkinson et al., 1986): nodes on even levels of the
+// tree are no greater than their descendants and nodes on odd levels are no
+// less than their descendants. The default value of MinMaxHeap is a valid empty
+// heap.
+type MinMaxHeap[T any] struct {
+	sl []T
+	nocopy.NoCopy
+}
+
+// LenMinMax returns the number of elements in the heap.
+func LenMinMax[T any](heap *MinMaxHeap[T]) int {
+	return len(heap.sl)
+}
+
+// PushMinMax adds an element to the heap for a T that satisfies
+// constraints.Ordered.
+func PushMinMax[T c.Ordered](heap *MinMaxHeap[T], elem T) {
+	pushMinMax(heap, elem, func(i, j int) int { return cmpOrdered(heap.sl[i], heap.sl[j]) })
+}
+
+// PushMinMaxOrderable adds an element to the heap for a T that implements
+// Orderable.
+func PushMinMaxOrderable[T Orderable[T]](heap *MinMaxHeap[T], elem T) {
+	pushMinMax(heap, elem, func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	})
+}
+
+func pushMinMax[T any](heap *MinMaxHeap[T], elem T, cmp func(i, j int) int) {
+	heap.sl = append(heap.sl, elem)
+	bubbleMinMax(heap, len(heap.sl)-1, cmp)
+}
+
+// PeekMin returns the min element of the heap without removing it.
+func PeekMin[T any](heap *MinMaxHeap[T]) (val T, ok bool) {
+	if len(heap.sl) == 0 {
+		return
+	}
+	ok = true
+	val = heap.sl[0]
+	return
+}
+
+// PeekMax returns the max element of the heap without removing it for a T that
+// satisfies constraints.Ordered.
+func PeekMax[T c.Ordered](heap *MinMaxHeap[T]) (T, bool) {
+	return peekMax(heap, func(i, j int) int { return cmpOrdered(heap.sl[i], heap.sl[j]) })
+}
+
+// As for PeekMax, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func PeekMaxOrderable[T Orderable[T]](heap *MinMaxHeap[T]) (T, bool) {
+	return peekMax(heap, func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	})
+}
+
+func peekMax[T any](heap *MinMaxHeap[T], cmp func(i, j int) int) (val T, ok bool) {
+	if len(heap.sl) == 0 {
+		return
+	}
+	ok = true
+	val = heap.sl[maxIndexMinMax(heap, cmp)]
+	return
+}
+
+// PopMin removes the min element from the heap for a T that satisfies
+// constraints.Ordered.
+func PopMin[T c.Ordered](heap *MinMaxHeap[T]) (T, bool) {
+	return popMinMaxAt(heap, 0, func(i, j int) int { return cmpOrdered(heap.sl[i], heap.sl[j]) })
+}
+
+// As for PopMin, but for the case where T cannot be compared using < and there
+// is an implementation of Orderable[T].
+func PopMinOrderable[T Orderable[T]](heap *MinMaxHeap[T]) (T, bool) {
+	return popMinMaxAt(heap, 0, func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	})
+}
+
+// PopMax removes the max element from the heap for a T that satisfies
+// constraints.Ordered.
+func PopMax[T c.Ordered](heap *MinMaxHeap[T]) (T, bool) {
+	cmp := func(i, j int) int { return cmpOrdered(heap.sl[i], heap.sl[j]) }
+	return popMinMaxAt(heap, maxIndexMinMax(heap, cmp), cmp)
+}
+
+// As for PopMax, but for the case where T cannot be compared using < and there
+// is an implementation of Orderable[T].
+func PopMaxOrderable[T Orderable[T]](heap *MinMaxHeap[T]) (T, bool) {
+	cmp := func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	}
+	return popMinMaxAt(heap, maxIndexMinMax(heap, cmp), cmp)
+}
+
+func popMinMaxAt[T any](heap *MinMaxHeap[T], i int, cmp func(i, j int) int) (val T, ok bool) {
+	if len(heap.sl) == 0 {
+		return
+	}
+
+	ok = true
+	val = heap.sl[i]
+
+	if i+1 == len(heap.sl) {
+		heap.sl = shrink(heap.sl)
+		return
+	}
+
+	heap.sl[i] = heap.sl[len(heap.sl)-1]
+	heap.sl = shrink(heap.sl)
+	trickleDownMinMax(heap, i, cmp)
+
+	return
+}
+
+// ClearMinMax empties the heap.
+func ClearMinMax[T any](heap *MinMaxHeap[T]) {
+	heap.sl = nil
+}
+
+// FromSliceMinMax initializes the heap from the elements of a slice using
+// Floyd's heap-building algorithm. The previous contents of the heap (if any)
+// are discarded. The slice is 'moved' into the heap and should not be accessed
+// or modified following a call to this function.
+func FromSliceMinMax[T c.Ordered](heap *MinMaxHeap[T], slice []T) {
+	fromSliceMinMax(heap, slice, func(i, j int) int {
+		return cmpOrdered(heap.sl[i], heap.sl[j])
+	})
+}
+
+// As for FromSliceMinMax, but for the case where T cannot be compared using <
+// and there is an implementation of Orderable[T].
+func FromSliceMinMaxOrderable[T Orderable[T]](heap *MinMaxHeap[T], slice []T) {
+	fromSliceMinMax(heap, slice, func(i, j int) int {
+		return heap.sl[i].Cmp(heap.sl[j])
+	})
+}
+
+func fromSliceMinMax[T any](heap *MinMaxHeap[T], slice []T, cmp func(int, int) int) {
+	// ensure that there's no associated heap allocation if the heap is empty
+	if len(slice) == 0 {
+		heap.sl = nil
+		return
+	}
+
+	heap.sl = slice
+	for i := parentIndex(len(heap.sl) - 1); i >= 0; i-- {
+		trickleDownMinMax(heap, i, cmp)
+	}
+}
+
+// The direction of a level is 1 for a min level and -1 for a max level, so that
+// dir*cmp(i, j) < 0 means that i belongs nearer the root than j on a level
+// with direction dir (cf. MinOrMax.mul).
+func levelDirMinMax(i int) int {
+	if bits.Len(uint(i+1))%2 == 1 {
+		return 1
+	}
+	return -1
+}
+
+func maxIndexMinMax[T any](heap *MinMaxHeap[T], cmp func(i, j int) int) int {
+	switch len(heap.sl) {
+	case 0, 1:
+		return 0
+	case 2:
+		return 1
+	}
+	if cmp(2, 1) > 0 {
+		return 2
+	}
+	return 1
+}
+
+func bubbleMinMax[T any](heap *MinMaxHeap[T], i int, cmp func(i, j int) int) {
+	if i == 0 {
+		return
+	}
+
+	dir := levelDirMinMax(i)
+	pi := parentIndex(i)
+	if dir*cmp(i, pi) > 0 {
+		// i belongs on the parent's level
+		heap.sl[i], heap.sl[pi] = heap.sl[pi], heap.sl[i]
+		i = pi
+		dir = -dir
+	}
+
+	// now bubble up through the grandparents, which are on the same level
+	for i > 2 {
+		gpi := parentIndex(parentIndex(i))
+		if dir*cmp(i, gpi) >= 0 {
+			break
+		}
+		heap.sl[i], heap.sl[gpi] = heap.sl[gpi], heap.sl[i]
+		i = gpi
+	}
+}
+
+func trickleDownMinMax[T any](heap *MinMaxHeap[T], i int, cmp func(i, j int) int) {
+	dir := levelDirMinMax(i)
+
+	for {
+		// find the best of the children and grandchildren of i
+		lci := leftChildIndex(i)
+		if lci >= len(heap.sl) {
+			return
+		}
+		best := lci
+		for _, j := range [...]int{rightChildIndex(i), leftChildIndex(lci), rightChildIndex(lci), leftChildIndex(lci + 1), rightChildIndex(lci + 1)} {
+			if j < len(heap.sl) && dir*cmp(j, best) < 0 {
+				best = j
+			}
+		}
+
+		if dir*cmp(best, i) >= 0 {
+			return
+		}
+		heap.sl[i], heap.sl[best] = heap.sl[best], heap.sl[i]
+
+		if best <= rightChildIndex(i) {
+			// best was a child, so it has no descendants on i's level
+			return
+		}
+
+		// best was a grandchild; the element displaced from i may belong on the
+		// level in between
+		if pi := parentIndex(best); dir*cmp(best, pi) > 0 {
+			heap.sl[best], heap.sl[pi] = heap.sl[pi], heap.sl[best]
+		}
+		i = best
+	}
+}
@@ -0,0 +1,189 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestMinMaxPushAndPop(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14, 0, 3, 3, 7, 22, -8, 11}
+	var heap MinMaxHeap[int]
+	for _, elem := range elems {
+		PushMinMax(&heap, elem)
+	}
+	if !checkMinMaxHeapProperty(&heap) {
+		t.Errorf("Min-max heap property violated")
+	}
+	sort.Ints(elems)
+	lo, hi := 0, len(elems)-1
+	for lo <= hi {
+		if v, ok := PeekMin(&heap); !ok || v != elems[lo] {
+			t.Errorf("Expected min (%v,true), got (%v,%v)\n", elems[lo], v, ok)
+		}
+		if v, ok := PeekMax(&heap); !ok || v != elems[hi] {
+			t.Errorf("Expected max (%v,true), got (%v,%v)\n", elems[hi], v, ok)
+		}
+		if (hi-lo)%2 == 0 {
+			v, _ := PopMin(&heap)
+			if v != elems[lo] {
+				t.Errorf("Expected PopMin to return %v, got %v\n", elems[lo], v)
+			}
+			lo++
+		} else {
+			v, _ := PopMax(&heap)
+			if v != elems[hi] {
+				t.Errorf("Expected PopMax to return %v, got %v\n", elems[hi], v)
+			}
+			hi--
+		}
+		if !checkMinMaxHeapProperty(&heap) {
+			t.Errorf("Min-max heap property violated")
+		}
+	}
+	if heap.sl != nil {
+		t.Errorf("Expecting empty heap to have nil backing slice")
+	}
+}
+
+func TestMinMaxEmpty(t *testing.T) {
+	var heap MinMaxHeap[int]
+	if _, ok := PeekMin(&heap); ok {
+		t.Errorf("Calling PeekMin on an empty heap should have returned ok=false")
+	}
+	if _, ok := PeekMax(&heap); ok {
+		t.Errorf("Calling PeekMax on an empty heap should have returned ok=false")
+	}
+	if _, ok := PopMin(&heap); ok {
+		t.Errorf("Calling PopMin on an empty heap should have returned ok=false")
+	}
+	if _, ok := PopMax(&heap); ok {
+		t.Errorf("Calling PopMax on an empty heap should have returned ok=false")
+	}
+}
+
+func TestMinMaxSmallHeaps(t *testing.T) {
+	var heap MinMaxHeap[int]
+	PushMinMax(&heap, 1)
+	if v, _ := PeekMax(&heap); v != 1 {
+		t.Errorf("Expected max of single element heap to be 1, got %v\n", v)
+	}
+	PushMinMax(&heap, 2)
+	if v, _ := PopMax(&heap); v != 2 {
+		t.Errorf("Expected PopMax to return 2, got %v\n", v)
+	}
+	if v, _ := PopMax(&heap); v != 1 {
+		t.Errorf("Expected PopMax to return 1, got %v\n", v)
+	}
+}
+
+func TestMinMaxOrderable(t *testing.T) {
+	var heap MinMaxHeap[myCustomType]
+	for _, k := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		PushMinMaxOrderable(&heap, myCustomType{Key: k})
+	}
+	if v, _ := PeekMaxOrderable(&heap); v.Key != 9 {
+		t.Errorf("Expected max key 9, got %v\n", v.Key)
+	}
+	if v, _ := PopMaxOrderable(&heap); v.Key != 9 {
+		t.Errorf("Expected max key 9, got %v\n", v.Key)
+	}
+	if v, _ := PopMinOrderable(&heap); v.Key != 1 {
+		t.Errorf("Expected min key 1, got %v\n", v.Key)
+	}
+	if v, _ := PopMaxOrderable(&heap); v.Key != 6 {
+		t.Errorf("Expected max key 6, got %v\n", v.Key)
+	}
+}
+
+func TestFromSliceMinMaxFuzz(t *testing.T) {
+	src := rand.NewSource(123)
+	for n := 0; n < 200; n++ {
+		slice := make([]int, n)
+		for i := range slice {
+			slice[i] = int(src.Int63() % 50)
+		}
+		cp := make([]int, len(slice))
+		copy(cp, slice)
+		var heap MinMaxHeap[int]
+		FromSliceMinMax(&heap, slice)
+		if !checkMinMaxHeapProperty(&heap) {
+			t.Fatalf("Min-max heap property violated for %v\n", cp)
+		}
+		if !slicesHaveSameElems(cp, heap.sl) {
+			t.Fatalf("Elems not the same")
+		}
+	}
+}
+
+func TestFromSliceMinMaxOrderable(t *testing.T) {
+	slice := []myOtherCustomType{{91}, {21}, {76}, {73}, {23}, {25}, {14}, {95}, {36}, {76}, {49}, {97}, {310}, {-11}, {-33}, {-12}, {155}, {979}, {190}, {175}}
+	var heap MinMaxHeap[myOtherCustomType]
+	FromSliceMinMaxOrderable(&heap, slice)
+	ints := make([]int, len(slice))
+	for i := range heap.sl {
+		ints[i] = heap.sl[i].v
+	}
+	if !checkMinMaxHeapProperty(&MinMaxHeap[int]{sl: ints}) {
+		t.Errorf("Min-max heap property violated")
+	}
+}
+
+// Fuzz tests a randomly generated sequence of operations against the same set
+// of operations performed on a sorted slice.
+func TestMinMaxHeapFuzz(t *testing.T) {
+	src := rand.NewSource(123)
+
+	var realHeap MinMaxHeap[int]
+	var naiveHeap []int
+
+	for i := 0; i < 10000; i++ {
+		rnd := src.Int63()
+		if rnd%11 == 0 {
+			v1, ok1 := naiveHeapPop(&naiveHeap)
+			v2, ok2 := PopMin(&realHeap)
+			if v1 != v2 || ok1 != ok2 {
+				t.Fatalf("PopMin: got %v,%v, expected %v,%v\n", v2, ok2, v1, ok1)
+			}
+		} else if rnd%13 == 0 {
+			v1, ok1 := naiveHeapPopMax(&naiveHeap)
+			v2, ok2 := PopMax(&realHeap)
+			if v1 != v2 || ok1 != ok2 {
+				t.Fatalf("PopMax: got %v,%v, expected %v,%v\n", v2, ok2, v1, ok1)
+			}
+		} else {
+			v := int(rnd % 100)
+			naiveMinHeapPush(&naiveHeap, v)
+			PushMinMax(&realHeap, v)
+		}
+
+		if !checkMinMaxHeapProperty(&realHeap) {
+			t.Fatalf("Real heap does not have min-max heap property: %+v\n", realHeap.sl)
+		}
+
+		if !slicesHaveSameElems(naiveHeap, realHeap.sl) {
+			t.Fatalf("Elements not the same:\n%+v\n\n%+v\n", naiveHeap, realHeap.sl)
+		}
+	}
+
+	for i := 0; ; i++ {
+		var v1, v2 int
+		var ok1, ok2 bool
+		if i%2 == 0 {
+			v1, ok1 = PopMin(&realHeap)
+			v2, ok2 = naiveHeapPop(&naiveHeap)
+		} else {
+			v1, ok1 = PopMax(&realHeap)
+			v2, ok2 = naiveHeapPopMax(&naiveHeap)
+		}
+
+		if v1 != v2 || ok1 != ok2 {
+			t.Errorf("Oh no! Got %v,%v, expected %v,%v.\n", v1, ok1, v2, ok2)
+			break
+		}
+
+		if !ok1 {
+			break
+		}
+	}
+}
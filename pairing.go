@@ -0,0 +1,261 @@
+package heap
+
+import (
+	"github.com/savsgio/gotils/nocopy"
+	c "golang.org/x/exp/constraints"
+)
+
+// PairingHeap is a min or max pairing heap (Fredman et al., 1986). Unlike
+// Heap, it is node-based rather than slice-based, which allows two pairing
+// heaps to be melded in O(1) time. Push and Meld are O(1), Pop is O(log n)
+// amortized, and DecreaseKeyPairing (which moves an element towards the root)
+// is at worst O(log n) amortized. The default value of PairingHeap is a valid
+// empty heap.
+type PairingHeap[T any, MOM MinOrMax] struct {
+	root *pairingNode[T]
+	n    int
+	nocopy.NoCopy
+}
+
+type pairingNode[T any] struct {
+	val T
+	// child is the leftmost child and sibling the next sibling to the right.
+	// prev is the previous sibling, or the parent for a leftmost child.
+	child, sibling, prev *pairingNode[T]
+	removed              bool
+}
+
+// A PairingHandle refers to an element of a PairingHeap. A handle is valid
+// until the element it refers to is popped from the heap or the heap is
+// cleared. Handles remain valid when the heap containing the element is melded
+// into another heap, in which case they refer to the element in the other
+// heap. The zero value of PairingHandle is never valid.
+type PairingHandle[T any] struct {
+	n *pairingNode[T]
+}
+
+// LenPairing returns the number of elements in the heap.
+func LenPairing[T any, MOM MinOrMax](heap *PairingHeap[T, MOM]) int {
+	return heap.n
+}
+
+// PushPairing adds an element to the heap for a T that satisfies
+// constraints.Ordered and returns a handle to the element.
+func PushPairing[T c.Ordered, MOM MinOrMax](heap *PairingHeap[T, MOM], elem T) PairingHandle[T] {
+	return pushPairing(heap, elem, cmpOrdered[T])
+}
+
+// PushPairingOrderable adds an element to the heap for a T that implements
+// Orderable and returns a handle to the element.
+func PushPairingOrderable[T Orderable[T], MOM MinOrMax](heap *PairingHeap[T, MOM], elem T) PairingHandle[T] {
+	return pushPairing(heap, elem, T.Cmp)
+}
+
+func pushPairing[T any, MOM MinOrMax](heap *PairingHeap[T, MOM], elem T, cmp func(a, b T) int) PairingHandle[T] {
+	n := &pairingNode[T]{val: elem}
+	heap.root = meldPairingNodes[T, MOM](heap.root, n, cmp)
+	heap.n++
+	return PairingHandle[T]{n}
+}
+
+// PopPairing removes the min/max element from the heap for a T that satisfies
+// constraints.Ordered.
+func PopPairing[T c.Ordered, MOM MinOrMax](heap *PairingHeap[T, MOM]) (T, bool) {
+	return popPairing(heap, cmpOrdered[T])
+}
+
+// PopPairingOrderable removes the min/max element from the heap for a T that
+// implements Orderable.
+func PopPairingOrderable[T Orderable[T], MOM MinOrMax](heap *PairingHeap[T, MOM]) (T, bool) {
+	return popPairing(heap, T.Cmp)
+}
+
+func popPairing[T any, MOM MinOrMax](heap *PairingHeap[T, MOM], cmp func(a, b T) int) (val T, ok bool) {
+	if heap.root == nil {
+		return
+	}
+
+	ok = true
+	r := heap.root
+	val = r.val
+	heap.root = mergePairs[T, MOM](r.child, cmp)
+	heap.n--
+
+	*r = pairingNode[T]{removed: true}
+	return
+}
+
+// PeekPairing returns the min/max element from the heap without removing it.
+func PeekPairing[T any, MOM MinOrMax](heap *PairingHeap[T, MOM]) (val T, ok bool) {
+	if heap.root == nil {
+		return
+	}
+	ok = true
+	val = heap.root.val
+	return
+}
+
+// GetPairing returns the value of the element referred to by the handle. The
+// second return value is false if the handle is not valid.
+func GetPairing[T any](h PairingHandle[T]) (val T, ok bool) {
+	if h.n == nil || h.n.removed {
+		return
+	}
+	ok = true
+	val = h.n.val
+	return
+}
+
+// MeldPairing moves all of the elements of src into dst in O(1) time for a T
+// that satisfies constraints.Ordered. src is left empty. Handles to elements of
+// src remain valid and now refer to elements of dst.
+func MeldPairing[T c.Ordered, MOM MinOrMax](dst, src *PairingHeap[T, MOM]) {
+	meldPairing(dst, src, cmpOrdered[T])
+}
+
+// As for MeldPairing, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func MeldPairingOrderable[T Orderable[T], MOM MinOrMax](dst, src *PairingHeap[T, MOM]) {
+	meldPairing(dst, src, T.Cmp)
+}
+
+func meldPairing[T any, MOM MinOrMax](dst, src *PairingHeap[T, MOM], cmp func(a, b T) int) {
+	if dst == src {
+		return
+	}
+	dst.root = meldPairingNodes[T, MOM](dst.root, src.root, cmp)
+	dst.n += src.n
+	src.root = nil
+	src.n = 0
+}
+
+// DecreaseKeyPairing replaces the value of the element referred to by the
+// handle with a value that is no further from the root (i.e. no greater for a
+// min heap and no less for a max heap) for a T that satisfies
+// constraints.Ordered. The handle must refer to an element of heap. Returns
+// false without modifying the heap if the handle is not valid or the new value
+// is further from the root than the old one.
+func DecreaseKeyPairing[T c.Ordered, MOM MinOrMax](heap *PairingHeap[T, MOM], h PairingHandle[T], val T) bool {
+	return decreaseKeyPairing(heap, h, val, cmpOrdered[T])
+}
+
+// As for DecreaseKeyPairing, but for the case where T cannot be compared using
+// < and there is an implementation of Orderable[T].
+func DecreaseKeyPairingOrderable[T Orderable[T], MOM MinOrMax](heap *PairingHeap[T, MOM], h PairingHandle[T], val T) bool {
+	return decreaseKeyPairing(heap, h, val, T.Cmp)
+}
+
+func decreaseKeyPairing[T any, MOM MinOrMax](heap *PairingHeap[T, MOM], h PairingHandle[T], val T, cmp func(a, b T) int) bool {
+	var mom MOM
+
+	n := h.n
+	if n == nil || n.removed || mom.mul()*cmp(val, n.val) > 0 {
+		return false
+	}
+
+	n.val = val
+	if n == heap.root {
+		return true
+	}
+
+	// cut the subtree rooted at n from its parent and meld it with the root
+	if n.prev.child == n {
+		n.prev.child = n.sibling
+	} else {
+		n.prev.sibling = n.sibling
+	}
+	if n.sibling != nil {
+		n.sibling.prev = n.prev
+	}
+	n.sibling = nil
+	n.prev = nil
+	heap.root = meldPairingNodes[T, MOM](heap.root, n, cmp)
+
+	return true
+}
+
+// ClearPairing empties the heap. All handles to elements of the heap become
+// invalid.
+func ClearPairing[T any, MOM MinOrMax](heap *PairingHeap[T, MOM]) {
+	// Walk the tree without recursion, as it may be very deep
+	stack := []*pairingNode[T]{}
+	if heap.root != nil {
+		stack = append(stack, heap.root)
+	}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for c := n.child; c != nil; c = c.sibling {
+			stack = append(stack, c)
+		}
+		*n = pairingNode[T]{removed: true}
+	}
+	heap.root = nil
+	heap.n = 0
+}
+
+func meldPairingNodes[T any, MOM MinOrMax](a, b *pairingNode[T], cmp func(a, b T) int) *pairingNode[T] {
+	var mom MOM
+
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if mom.mul()*cmp(b.val, a.val) < 0 {
+		a, b = b, a
+	}
+
+	// make b the leftmost child of a
+	b.prev = a
+	b.sibling = a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	a.child = b
+	a.sibling = nil
+	a.prev = nil
+	return a
+}
+
+// mergePairs melds a list of siblings into a single tree using the standard
+// two-pass method: first meld pairs from left to right, then meld the
+// resulting trees from right to left.
+func mergePairs[T any, MOM MinOrMax](first *pairingNode[T], cmp func(a, b T) int) *pairingNode[T] {
+	if first == nil {
+		return nil
+	}
+
+	// First pass. The melded pairs are chained together in reverse order via
+	// their sibling pointers, ready for the second pass.
+	var pairs *pairingNode[T]
+	for first != nil {
+		a := first
+		b := a.sibling
+		if b == nil {
+			first = nil
+		} else {
+			first = b.sibling
+		}
+		a.sibling, a.prev = nil, nil
+		if b != nil {
+			b.sibling, b.prev = nil, nil
+		}
+		m := meldPairingNodes[T, MOM](a, b, cmp)
+		m.sibling = pairs
+		pairs = m
+	}
+
+	// Second pass
+	root := pairs
+	pairs = pairs.sibling
+	root.sibling = nil
+	for pairs != nil {
+		next := pairs.sibling
+		pairs.sibling = nil
+		root = meldPairingNodes[T, MOM](root, pairs, cmp)
+		pairs = next
+	}
+	return root
+}
@@ -0,0 +1,180 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestPairingPushAndPop(t *testing.T) {
+	elems := []int{1, 5, 2, 9, -3, 17, 18, 19, 14, 0, 3, 3, 7, 22, -8, 11}
+	var heap PairingHeap[int, Min]
+	for _, elem := range elems {
+		PushPairing(&heap, elem)
+	}
+	if LenPairing(&heap) != len(elems) {
+		t.Errorf("Expected heap to have length %v, got %v\n", len(elems), LenPairing(&heap))
+	}
+	sort.Ints(elems)
+	for i := 0; i < len(elems); i++ {
+		if v, ok := PeekPairing(&heap); !ok || v != elems[i] {
+			t.Errorf("Expected peek of (%v,true), got (%v,%v)\n", elems[i], v, ok)
+		}
+		v, ok := PopPairing(&heap)
+		if !ok || v != elems[i] {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", elems[i], v, ok)
+		}
+	}
+	if _, ok := PopPairing(&heap); ok {
+		t.Errorf("Calling PopPairing on an empty heap should have returned ok=false")
+	}
+}
+
+func TestPairingMeld(t *testing.T) {
+	var a, b PairingHeap[int, Max]
+	for i := 0; i < 10; i += 2 {
+		PushPairing(&a, i)
+	}
+	var handles []PairingHandle[int]
+	for i := 1; i < 10; i += 2 {
+		handles = append(handles, PushPairing(&b, i))
+	}
+	MeldPairing(&a, &b)
+	if LenPairing(&a) != 10 || LenPairing(&b) != 0 {
+		t.Errorf("Unexpected lengths after meld: %v %v\n", LenPairing(&a), LenPairing(&b))
+	}
+	if _, ok := PopPairing(&b); ok {
+		t.Errorf("Expected source heap to be empty after meld")
+	}
+
+	// handles into b should now refer to elements of a
+	if !DecreaseKeyPairing(&a, handles[0], 100) {
+		t.Errorf("Expected DecreaseKeyPairing to succeed")
+	}
+
+	expected := []int{100, 9, 8, 7, 6, 5, 4, 3, 2, 0}
+	for _, e := range expected {
+		if v, ok := PopPairing(&a); !ok || v != e {
+			t.Errorf("Expected (%v,true), got (%v,%v)\n", e, v, ok)
+		}
+	}
+}
+
+func TestPairingDecreaseKey(t *testing.T) {
+	var heap PairingHeap[int, Min]
+	handles := make([]PairingHandle[int], 10)
+	for i := range handles {
+		handles[i] = PushPairing(&heap, i*10)
+	}
+	PopPairing(&heap) // force the tree to be restructured
+
+	if !DecreaseKeyPairing(&heap, handles[7], 5) {
+		t.Errorf("Expected DecreaseKeyPairing to succeed")
+	}
+	if v, _ := PeekPairing(&heap); v != 5 {
+		t.Errorf("Expected 5 at root, got %v\n", v)
+	}
+	if DecreaseKeyPairing(&heap, handles[7], 6) {
+		t.Errorf("Expected DecreaseKeyPairing to fail when increasing the key")
+	}
+	if DecreaseKeyPairing(&heap, handles[0], -1) {
+		t.Errorf("Expected DecreaseKeyPairing to fail for a popped element")
+	}
+	if _, ok := GetPairing(handles[0]); ok {
+		t.Errorf("Expected popped handle to be invalid")
+	}
+	if v, ok := GetPairing(handles[7]); !ok || v != 5 {
+		t.Errorf("Expected (5,true), got (%v,%v)\n", v, ok)
+	}
+
+	ClearPairing(&heap)
+	if _, ok := GetPairing(handles[3]); ok {
+		t.Errorf("Expected handle to be invalid after clear")
+	}
+	if LenPairing(&heap) != 0 {
+		t.Errorf("Expected empty heap after clear")
+	}
+}
+
+func TestPairingOrderable(t *testing.T) {
+	var a, b PairingHeap[myCustomType, Min]
+	PushPairingOrderable(&a, myCustomType{Key: 3, Content: "c"})
+	h := PushPairingOrderable(&b, myCustomType{Key: 5, Content: "e"})
+	PushPairingOrderable(&b, myCustomType{Key: 2, Content: "b"})
+	MeldPairingOrderable(&a, &b)
+	DecreaseKeyPairingOrderable(&a, h, myCustomType{Key: 1, Content: "a"})
+	var s string
+	for {
+		v, ok := PopPairingOrderable(&a)
+		if !ok {
+			break
+		}
+		s += v.Content
+	}
+	if s != "abc" {
+		t.Errorf("Unexpected pop order: %v\n", s)
+	}
+}
+
+// Fuzz tests a randomly generated sequence of operations against the same set
+// of operations performed on a sorted slice.
+func TestPairingHeapFuzz(t *testing.T) {
+	src := rand.NewSource(123)
+
+	var realHeap PairingHeap[int, Min]
+	var naiveHeap []int
+	var handles []PairingHandle[int]
+
+	for i := 0; i < 10000; i++ {
+		rnd := src.Int63()
+		if rnd%13 == 0 {
+			v1, ok1 := naiveHeapPop(&naiveHeap)
+			v2, ok2 := PopPairing(&realHeap)
+			if v1 != v2 || ok1 != ok2 {
+				t.Fatalf("Got %v,%v, expected %v,%v\n", v2, ok2, v1, ok1)
+			}
+		} else if rnd%7 == 0 && len(handles) > 0 {
+			h := handles[int(rnd/7)%len(handles)]
+			old, ok := GetPairing(h)
+			if !ok {
+				continue
+			}
+			v := old - int((rnd/91)%20)
+			naiveHeapRemove(&naiveHeap, old)
+			naiveMinHeapPush(&naiveHeap, v)
+			if !DecreaseKeyPairing(&realHeap, h, v) {
+				t.Fatalf("Expected DecreaseKeyPairing to succeed")
+			}
+		} else if rnd%29 == 0 {
+			var other PairingHeap[int, Min]
+			for j := 0; j < 5; j++ {
+				v := int((rnd >> j) % 100)
+				naiveMinHeapPush(&naiveHeap, v)
+				handles = append(handles, PushPairing(&other, v))
+			}
+			MeldPairing(&realHeap, &other)
+		} else {
+			v := int(rnd % 100)
+			naiveMinHeapPush(&naiveHeap, v)
+			handles = append(handles, PushPairing(&realHeap, v))
+		}
+
+		if LenPairing(&realHeap) != len(naiveHeap) {
+			t.Fatalf("Expected length %v, got %v\n", len(naiveHeap), LenPairing(&realHeap))
+		}
+	}
+
+	for {
+		v1, ok1 := PopPairing(&realHeap)
+		v2, ok2 := naiveHeapPop(&naiveHeap)
+
+		if v1 != v2 || ok1 != ok2 {
+			t.Errorf("Oh no! Got %v,%v, expected %v,%v.\n", v1, ok1, v2, ok2)
+			break
+		}
+
+		if !ok1 {
+			break
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package heap
+
+import (
+	"runtime"
+	"testing"
+	"weak"
+)
+
+// These tests check that removed elements are not kept reachable by the
+// backing slice of a heap.
+
+type payload struct {
+	key  int
+	data [1024]byte
+}
+
+type ptrElem struct {
+	p *payload
+}
+
+func (a ptrElem) Cmp(b ptrElem) int {
+	return a.p.key - b.p.key
+}
+
+// fillElems creates n elements with keys 0..n-1, passes each to push, and
+// returns weak pointers to their payloads.
+func fillElems(n int, push func(ptrElem)) []weak.Pointer[payload] {
+	ws := make([]weak.Pointer[payload], n)
+	for i := range n {
+		p := &payload{key: i}
+		ws[i] = weak.Make(p)
+		push(ptrElem{p})
+	}
+	return ws
+}
+
+func checkCollected(t *testing.T, ws []weak.Pointer[payload], collected func(key int) bool) {
+	t.Helper()
+	runtime.GC()
+	runtime.GC()
+	for key, w := range ws {
+		if collected(key) && w.Value() != nil {
+			t.Errorf("Expected element %v to have been collected\n", key)
+		}
+		if !collected(key) && w.Value() == nil {
+			t.Errorf("Expected element %v to still be reachable\n", key)
+		}
+	}
+}
+
+func TestPopReleasesElements(t *testing.T) {
+	var heap Heap[ptrElem, Min]
+	SetShrinkPolicy(&heap, ShrinkNever)
+	ws := fillElems(10, func(e ptrElem) { PushOrderable(&heap, e) })
+	for range 3 {
+		PopOrderable(&heap)
+	}
+	checkCollected(t, ws, func(key int) bool { return key < 3 })
+	runtime.KeepAlive(&heap)
+}
+
+func TestFilterReleasesElements(t *testing.T) {
+	var heap Heap[ptrElem, Max]
+	SetShrinkPolicy(&heap, ShrinkNever)
+	ws := fillElems(10, func(e ptrElem) { PushOrderable(&heap, e) })
+	FilterOrderable(&heap, func(e *ptrElem) (bool, BreakOrContinue) {
+		return e.p.key%2 == 0, Continue
+	})
+	checkCollected(t, ws, func(key int) bool { return key%2 == 1 })
+	runtime.KeepAlive(&heap)
+}
+
+func TestResetReleasesElements(t *testing.T) {
+	var heap Heap[ptrElem, Min]
+	ws := fillElems(10, func(e ptrElem) { PushOrderable(&heap, e) })
+	Reset(&heap)
+	checkCollected(t, ws, func(int) bool { return true })
+	if Cap(&heap) == 0 {
+		t.Errorf("Expected Reset to keep the backing slice")
+	}
+}
+
+func TestIndexedReleasesElements(t *testing.T) {
+	var heap IndexedHeap[ptrElem, Min]
+	var handles []Handle[ptrElem]
+	ws := fillElems(10, func(e ptrElem) { handles = append(handles, PushIndexedOrderable(&heap, e)) })
+	PopIndexedOrderable(&heap)
+	RemoveIndexedOrderable(&heap, handles[5])
+	checkCollected(t, ws, func(key int) bool { return key == 0 || key == 5 })
+	runtime.KeepAlive(handles)
+	runtime.KeepAlive(&heap)
+}
+
+func TestDaryReleasesElements(t *testing.T) {
+	var heap DaryHeap[ptrElem, Min, Arity4]
+	ws := fillElems(20, func(e ptrElem) { PushDaryOrderable(&heap, e) })
+	PopDaryOrderable(&heap)
+	FilterDaryOrderable(&heap, func(e *ptrElem) (bool, BreakOrContinue) {
+		return e.p.key != 10, Continue
+	})
+	checkCollected(t, ws, func(key int) bool { return key == 0 || key == 10 })
+	runtime.KeepAlive(&heap)
+}
+
+func TestMinMaxReleasesElements(t *testing.T) {
+	var heap MinMaxHeap[ptrElem]
+	ws := fillElems(10, func(e ptrElem) { PushMinMaxOrderable(&heap, e) })
+	PopMinOrderable(&heap)
+	PopMaxOrderable(&heap)
+	checkCollected(t, ws, func(key int) bool { return key == 0 || key == 9 })
+	runtime.KeepAlive(&heap)
+}
@@ -0,0 +1,219 @@
+package heap
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+	"unicode/utf8"
+)
+
+// RenderOptions configure Render and WriteDOT. The zero value gives the
+// default options.
+type RenderOptions[T any] struct {
+	// Format formats a single element. Defaults to formatting the element with
+	// the %v verb.
+	Format func(T) string
+	// MaxDepth is the maximum number of levels of the tree that are shown. If
+	// deeper levels are omitted, Render adds a final line stating how many
+	// elements are not shown. Zero means no limit.
+	MaxDepth int
+	// MaxWidth is the maximum width of the rendered tree in characters. Levels
+	// are omitted (as for MaxDepth) until the tree fits. If even the root
+	// doesn't fit, it's truncated. Zero means no limit. WriteDOT ignores
+	// MaxWidth.
+	MaxWidth int
+}
+
+func (opts *RenderOptions[T]) format(v T) string {
+	if opts.Format != nil {
+		return opts.Format(v)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// Render returns a drawing of the heap as a tree, with each level of the tree
+// on a separate line. For example:
+//
+//	          -3
+//	  1               2
+//	9       5       17      18
+//
+// An empty heap is rendered as an empty string.
+func Render[T any, MOM MinOrMax](heap *Heap[T, MOM], opts RenderOptions[T]) string {
+	a := heap.sl
+	if len(a) == 0 {
+		return ""
+	}
+
+	depth := bits.Len(uint(len(a)))
+	if opts.MaxDepth > 0 && opts.MaxDepth < depth {
+		depth = opts.MaxDepth
+	}
+
+	formatted := make([]string, min(len(a), 1<<depth-1))
+	for i := range formatted {
+		formatted[i] = opts.format(a[i])
+	}
+	for {
+		n := min(len(a), 1<<depth-1)
+		tree := renderTree(formatted[:n])
+		if opts.MaxWidth <= 0 || depth == 1 || renderedWidth(tree) <= opts.MaxWidth {
+			tree = tidyLines(tree, opts.MaxWidth)
+			if n < len(a) {
+				tree += fmt.Sprintf("... (%v more elements)\n", len(a)-n)
+			}
+			return tree
+		}
+		depth--
+	}
+}
+
+// WriteDOT writes a description of the heap as a tree to w in the Graphviz DOT
+// language. Each node is labelled with its element. RenderOptions.MaxDepth
+// limits the number of levels that are written.
+func WriteDOT[T any, MOM MinOrMax](w io.Writer, heap *Heap[T, MOM], opts RenderOptions[T]) error {
+	n := len(heap.sl)
+	if opts.MaxDepth > 0 && opts.MaxDepth < bits.Len(uint(n)) {
+		n = 1<<opts.MaxDepth - 1
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph heap {\n\tnode [shape=box];\n")
+	for i, v := range heap.sl[:n] {
+		fmt.Fprintf(&sb, "\tn%v [label=\"%v\"];\n", i, dotEscaper.Replace(opts.format(v)))
+		if i > 0 {
+			fmt.Fprintf(&sb, "\tn%v -> n%v;\n", parentIndex(i), i)
+		}
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Format implements fmt.Formatter. The %+v verb renders the heap as a tree
+// (see Render). All other verbs format the underlying slice, so e.g. %v prints
+// the elements in the order given by the slice. As Heap must not be copied, pass
+// a pointer to the heap to the fmt functions:
+//
+//	fmt.Printf("%+v\n", &h)
+func (heap *Heap[T, MOM]) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('+') {
+		if len(heap.sl) == 0 {
+			io.WriteString(f, "[]")
+			return
+		}
+		io.WriteString(f, strings.TrimSuffix(Render(heap, RenderOptions[T]{}), "\n"))
+		return
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), heap.sl)
+}
+
+func renderedWidth(tree string) int {
+	width := 0
+	for line := range strings.Lines(tree) {
+		width = max(width, utf8.RuneCountInString(strings.TrimRight(line, " \n")))
+	}
+	return width
+}
+
+// tidyLines removes trailing spaces and blank lines from a rendered tree and
+// truncates each line to width characters (if width > 0).
+func tidyLines(tree string, width int) string {
+	var sb strings.Builder
+	for line := range strings.Lines(tree) {
+		line = strings.TrimRight(line, " \n")
+		if line == "" {
+			continue
+		}
+		if width > 0 && utf8.RuneCountInString(line) > width {
+			line = string([]rune(line)[:width])
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// renderTree draws the formatted elements of a heap as a tree.
+func renderTree(elems []string) string {
+	var sb strings.Builder
+
+	bhl := 1
+	for bhl < len(elems) {
+		bhl *= 2
+	}
+
+	formatted := make([]string, bhl)
+	copy(formatted, elems)
+
+	maxLen := 0
+	for _, s := range elems {
+		maxLen = max(maxLen, utf8.RuneCountInString(s))
+	}
+	maxLen += 2
+
+	offsets := make([]int, bhl)
+	var f func(i int)
+	f = func(i int) {
+		if i >= bhl {
+			return
+		}
+
+		li := leftChildIndex(i)
+		ri := rightChildIndex(i)
+
+		if ri >= bhl {
+			if i*2 >= bhl {
+				offsets[i] = maxLen + offsets[i-1]
+			} else {
+				offsets[i] = maxLen / 2
+			}
+		} else {
+			f(li)
+			f(ri)
+			offsets[i] = (offsets[li] + offsets[ri]) / 2
+		}
+	}
+
+	f(0)
+
+	startingOffset := (maxLen - utf8.RuneCountInString(formatted[bhl/2])) / 2
+	level := 0
+	off := 0
+	for {
+		currentOff := 0
+		wspace := func() {
+			if currentOff >= startingOffset {
+				sb.WriteByte(' ')
+			}
+			currentOff++
+		}
+
+		for i := off; i < off+(1<<level); i++ {
+			if i >= bhl {
+				return sb.String()
+			}
+
+			for currentOff+maxLen/2 < offsets[i] {
+				wspace()
+			}
+			l := utf8.RuneCountInString(formatted[i])
+			lpad := (maxLen - l) / 2
+			for j := 0; j < lpad; j++ {
+				wspace()
+			}
+			sb.WriteString(formatted[i])
+			currentOff += l
+			for j := 0; i+1 < off+(1<<level) && j < maxLen-l-lpad; j++ {
+				wspace()
+			}
+		}
+		sb.WriteByte('\n')
+
+		off += (1 << level)
+		level++
+	}
+}
@@ -0,0 +1,121 @@
+package heap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRenderMaxDepth(t *testing.T) {
+	var heap Heap[int, Min]
+	for _, elem := range []int{1, 5, 2, 9, -3, 17, 18, 19, 14} {
+		Push(&heap, elem)
+	}
+	const expected = `
+  -3
+1   2
+... (6 more elements)
+`
+	if layout := Render(&heap, RenderOptions[int]{MaxDepth: 2}); strings.TrimSpace(expected) != strings.TrimSpace(layout) {
+		t.Errorf("Unexpected heap layout:\n%v\n", layout)
+	}
+}
+
+func TestRenderMaxWidth(t *testing.T) {
+	var heap Heap[int, Min]
+	for i := range 100 {
+		Push(&heap, i)
+	}
+	layout := Render(&heap, RenderOptions[int]{MaxWidth: 40})
+	for line := range strings.Lines(layout) {
+		if len(strings.TrimSuffix(line, "\n")) > 40 {
+			t.Errorf("Line exceeds width limit: %q\n", line)
+		}
+	}
+	if !strings.HasPrefix(strings.TrimSpace(layout), "0\n") || !strings.Contains(layout, "more elements)") {
+		t.Errorf("Unexpected heap layout:\n%v\n", layout)
+	}
+
+	// The root is truncated if it's too wide by itself.
+	layout = Render(&heap, RenderOptions[int]{MaxWidth: 3, Format: func(int) string { return "abcdef" }})
+	if !strings.HasPrefix(layout, "abc\n") {
+		t.Errorf("Unexpected heap layout:\n%q\n", layout)
+	}
+}
+
+func TestRenderFormat(t *testing.T) {
+	var heap Heap[myCustomType, Max]
+	PushOrderable(&heap, myCustomType{Key: 1, Content: "foo"})
+	PushOrderable(&heap, myCustomType{Key: 5, Content: "bar"})
+	PushOrderable(&heap, myCustomType{Key: 2, Content: "amp"})
+	const expected = `
+  bar
+foo  amp
+`
+	layout := Render(&heap, RenderOptions[myCustomType]{Format: func(v myCustomType) string { return v.Content }})
+	if strings.TrimSpace(expected) != strings.TrimSpace(layout) {
+		t.Errorf("Unexpected heap layout:\n%v\n", layout)
+	}
+	if layout := Render(&Heap[int, Min]{}, RenderOptions[int]{}); layout != "" {
+		t.Errorf("Expected empty heap to render as empty string, got %q\n", layout)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var heap Heap[string, Min]
+	FromSlice(&heap, []string{`b"`, "a", "c", "d"})
+	var sb strings.Builder
+	if err := WriteDOT(&sb, &heap, RenderOptions[string]{MaxDepth: 2}); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	const expected = `digraph heap {
+	node [shape=box];
+	n0 [label="a"];
+	n1 [label="b\""];
+	n0 -> n1;
+	n2 [label="c"];
+	n0 -> n2;
+}
+`
+	if sb.String() != expected {
+		t.Errorf("Unexpected DOT output:\n%v\n", sb.String())
+	}
+}
+
+func TestRenderLargeMaxDepth(t *testing.T) {
+	var heap Heap[int, Min]
+	FromSlice(&heap, []int{1, 2, 3, 4})
+	var expected strings.Builder
+	WriteDOT(&expected, &heap, RenderOptions[int]{})
+	for _, depth := range []int{3, 4, 62, 63, 64, 1000} {
+		var sb strings.Builder
+		if err := WriteDOT(&sb, &heap, RenderOptions[int]{MaxDepth: depth}); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if sb.String() != expected.String() {
+			t.Errorf("Unexpected DOT output for MaxDepth %v:\n%v\n", depth, sb.String())
+		}
+		if s := Render(&heap, RenderOptions[int]{MaxDepth: depth}); s != Render(&heap, RenderOptions[int]{}) {
+			t.Errorf("Unexpected output for MaxDepth %v:\n%v\n", depth, s)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	var heap Heap[int, Min]
+	for _, elem := range []int{3, 1, 2} {
+		Push(&heap, elem)
+	}
+	if s := fmt.Sprintf("%v", &heap); s != "[1 3 2]" {
+		t.Errorf("Unexpected %%v output: %q\n", s)
+	}
+	if s := fmt.Sprintf("%03d", &heap); s != "[001 003 002]" {
+		t.Errorf("Unexpected %%03d output: %q\n", s)
+	}
+	if s := fmt.Sprintf("%+v", &heap); strings.TrimSpace(s) != strings.TrimSpace(Render(&heap, RenderOptions[int]{})) {
+		t.Errorf("Unexpected %%+v output:\n%v\n", s)
+	}
+	if s := fmt.Sprintf("%+v", &Heap[int, Min]{}); s != "[]" {
+		t.Errorf("Unexpected %%+v output for empty heap: %q\n", s)
+	}
+}
@@ -0,0 +1,112 @@
+package heap
+
+import (
+	"iter"
+
+	c "golang.org/x/exp/constraints"
+)
+
+// Smallest returns the n smallest elements of seq in ascending order, for a T
+// that satisfies constraints.Ordered. If seq has fewer than n elements then all
+// of them are returned. Elements that compare equal are returned in an
+// unspecified order. Use slices.Values to pass a slice.
+//
+// At most n elements are held in memory at once (in a bounded heap), so
+// Smallest takes O(len log n) time for a sequence of length len. If the
+// sequence turns out to have no more than n elements, they are simply sorted.
+func Smallest[T c.Ordered](n int, seq iter.Seq[T]) []T {
+	return selectN[T, Min](n, seq, cmpOrdered[T])
+}
+
+// Largest returns the n largest elements of seq in descending order, for a T
+// that satisfies constraints.Ordered. It works as for Smallest.
+func Largest[T c.Ordered](n int, seq iter.Seq[T]) []T {
+	return selectN[T, Max](n, seq, cmpOrdered[T])
+}
+
+// As for Smallest, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func SmallestOrderable[T Orderable[T]](n int, seq iter.Seq[T]) []T {
+	return selectN[T, Min](n, seq, T.Cmp)
+}
+
+// As for Largest, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func LargestOrderable[T Orderable[T]](n int, seq iter.Seq[T]) []T {
+	return selectN[T, Max](n, seq, T.Cmp)
+}
+
+// SmallestFunc returns the n elements of seq with the smallest keys in
+// ascending order of key. key is called exactly once for each element. It
+// otherwise works as for Smallest.
+func SmallestFunc[T any, K c.Ordered](n int, seq iter.Seq[T], key func(T) K) []T {
+	return selectNFunc[T, K, Min](n, seq, key)
+}
+
+// LargestFunc returns the n elements of seq with the largest keys in
+// descending order of key. key is called exactly once for each element. It
+// otherwise works as for Smallest.
+func LargestFunc[T any, K c.Ordered](n int, seq iter.Seq[T], key func(T) K) []T {
+	return selectNFunc[T, K, Max](n, seq, key)
+}
+
+type keyedElem[T any, K c.Ordered] struct {
+	key K
+	val T
+}
+
+func selectNFunc[T any, K c.Ordered, MOM MinOrMax](n int, seq iter.Seq[T], key func(T) K) []T {
+	keyed := selectN[keyedElem[T, K], MOM](n, func(yield func(keyedElem[T, K]) bool) {
+		for v := range seq {
+			if !yield(keyedElem[T, K]{key(v), v}) {
+				return
+			}
+		}
+	}, func(a, b keyedElem[T, K]) int { return cmpOrdered(a.key, b.key) })
+
+	if keyed == nil {
+		return nil
+	}
+	result := make([]T, len(keyed))
+	for i, e := range keyed {
+		result[i] = e.val
+	}
+	return result
+}
+
+// selectN returns the first n elements of seq in min/max order.
+func selectN[T any, MOM MinOrMax](n int, seq iter.Seq[T], cmp func(a, b T) int) []T {
+	var mom MOM
+
+	if n <= 0 {
+		return nil
+	}
+
+	// Collect the first n elements. If there are no more than that then there's
+	// nothing to select, so they're just sorted.
+	var buf []T
+	var heap Heap[T, oppositeOf[MOM]]
+	hcmp := func(i, j int) int { return cmp(heap.sl[i], heap.sl[j]) }
+	for v := range seq {
+		if len(buf) < n {
+			buf = append(buf, v)
+			continue
+		}
+		if heap.sl == nil {
+			// The heap has the opposite ordering so that its root is the worst of
+			// the n elements kept so far.
+			fromSlice(&heap, buf, hcmp)
+		}
+		if mom.mul()*cmp(v, heap.sl[0]) < 0 {
+			replace(&heap, v, hcmp)
+		}
+	}
+
+	if heap.sl == nil {
+		heapSort[T, MOM](buf, cmp)
+		return buf
+	}
+	result := heap.sl
+	sortHeap(&heap, result, hcmp)
+	return result
+}
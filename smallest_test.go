@@ -0,0 +1,75 @@
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSmallestAndLargest(t *testing.T) {
+	src := rand.NewSource(123)
+	for _, size := range []int{0, 1, 5, 100} {
+		s := randomInts(src, size)
+		sorted := slices.Clone(s)
+		slices.Sort(sorted)
+		reversed := slices.Clone(sorted)
+		slices.Reverse(reversed)
+		for _, n := range []int{-1, 0, 1, 3, size - 1, size, size + 3} {
+			m := min(max(n, 0), size)
+			if got := Smallest(n, slices.Values(s)); !slices.Equal(got, sorted[:m]) {
+				t.Errorf("Smallest(%v) of %v: expected %v, got %v\n", n, s, sorted[:m], got)
+			}
+			if got := Largest(n, slices.Values(s)); !slices.Equal(got, reversed[:m]) {
+				t.Errorf("Largest(%v) of %v: expected %v, got %v\n", n, s, reversed[:m], got)
+			}
+		}
+	}
+}
+
+func TestSmallestLargestOrderable(t *testing.T) {
+	s := []myCustomType{{3, "c"}, {1, "a"}, {4, "d"}, {5, "e"}, {9, "i"}, {2, "b"}}
+	if got := SmallestOrderable(2, slices.Values(s)); len(got) != 2 || got[0].Content != "a" || got[1].Content != "b" {
+		t.Errorf("Unexpected SmallestOrderable result: %v\n", got)
+	}
+	if got := LargestOrderable(3, slices.Values(s)); len(got) != 3 || got[0].Key != 9 || got[1].Key != 5 || got[2].Key != 4 {
+		t.Errorf("Unexpected LargestOrderable result: %v\n", got)
+	}
+}
+
+func TestSmallestLargestFunc(t *testing.T) {
+	words := strings.Fields("the quick brown fox jumps over the lazy dog")
+	calls := 0
+	key := func(w string) int {
+		calls++
+		return len(w)
+	}
+	got := LargestFunc(2, slices.Values(words), key)
+	if len(got) != 2 || len(got[0]) != 5 || len(got[1]) != 5 {
+		t.Errorf("Unexpected LargestFunc result: %v\n", got)
+	}
+	if calls != len(words) {
+		t.Errorf("Expected key to be called %v times, got %v\n", len(words), calls)
+	}
+	if got := SmallestFunc(1, slices.Values(words), key); !slices.Equal(got, []string{"the"}) && !slices.Equal(got, []string{"fox"}) && !slices.Equal(got, []string{"dog"}) {
+		t.Errorf("Unexpected SmallestFunc result: %v\n", got)
+	}
+	if got := SmallestFunc(0, slices.Values(words), key); got != nil {
+		t.Errorf("Expected nil, got %v\n", got)
+	}
+}
+
+func TestSmallestStopsEarly(t *testing.T) {
+	// Smallest must consume the whole sequence, but must also respect a
+	// sequence that stops on its own.
+	seq := func(yield func(int) bool) {
+		for i := 10; i > 0; i-- {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	if got := Smallest(3, seq); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v\n", got)
+	}
+}
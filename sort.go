@@ -0,0 +1,145 @@
+package heap
+
+import (
+	"fmt"
+
+	c "golang.org/x/exp/constraints"
+)
+
+// oppositeOf reverses the ordering of MOM, so that e.g. a heapsort into min
+// order can use a max heap.
+type oppositeOf[MOM MinOrMax] struct{}
+
+func (oppositeOf[MOM]) mul() int {
+	var mom MOM
+	return -mom.mul()
+}
+
+// Sort sorts a slice in place into min/max order (i.e. ascending order for Min
+// and descending order for Max) for a T that satisfies constraints.Ordered.
+// It uses heapsort, so it takes O(n log n) time in the worst case and doesn't
+// allocate. The sort is not stable.
+func Sort[T c.Ordered, MOM MinOrMax](s []T) {
+	heapSort[T, MOM](s, cmpOrdered[T])
+}
+
+// As for Sort, but for the case where T cannot be compared using < and there
+// is an implementation of Orderable[T].
+func SortOrderable[T Orderable[T], MOM MinOrMax](s []T) {
+	heapSort[T, MOM](s, T.Cmp)
+}
+
+// PartialSort rearranges a slice in place so that s[:k] holds the k min/max
+// elements of s in min/max order, for a T that satisfies constraints.Ordered.
+// The order of the remaining elements is unspecified. If k >= len(s) then the
+// whole slice is sorted. It takes O(n log k) time and doesn't allocate.
+func PartialSort[T c.Ordered, MOM MinOrMax](s []T, k int) {
+	partialSort[T, MOM](s, k, cmpOrdered[T])
+}
+
+// As for PartialSort, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func PartialSortOrderable[T Orderable[T], MOM MinOrMax](s []T, k int) {
+	partialSort[T, MOM](s, k, T.Cmp)
+}
+
+// NthElement rearranges a slice in place so that s[n] holds the element that
+// would be there if s were sorted into min/max order, for a T that satisfies
+// constraints.Ordered. No element of s[:n] is ordered after s[n] and no
+// element of s[n+1:] is ordered before it; the order within s[:n] and s[n+1:]
+// is otherwise unspecified. It takes O(len(s) log min(n+1, len(s)-n)) time and
+// doesn't allocate. NthElement panics if n is out of range.
+func NthElement[T c.Ordered, MOM MinOrMax](s []T, n int) {
+	nthElement[T, MOM](s, n, cmpOrdered[T])
+}
+
+// As for NthElement, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func NthElementOrderable[T Orderable[T], MOM MinOrMax](s []T, n int) {
+	nthElement[T, MOM](s, n, T.Cmp)
+}
+
+func heapSort[T any, MOM MinOrMax](s []T, cmp func(a, b T) int) {
+	// Build a heap with the opposite ordering, then repeatedly move its root to
+	// the end of the slice.
+	var heap Heap[T, oppositeOf[MOM]]
+	hcmp := func(i, j int) int { return cmp(heap.sl[i], heap.sl[j]) }
+	fromSlice(&heap, s, hcmp)
+	sortHeap(&heap, s, hcmp)
+	heap.sl = nil
+}
+
+// sortHeap sorts s, which must be the backing slice of heap.
+func sortHeap[T any, MOM MinOrMax](heap *Heap[T, MOM], s []T, cmp func(i, j int) int) {
+	for end := len(s) - 1; end > 0; end-- {
+		// As for pop, but the root is moved to the vacated slot at the end of the
+		// slice instead of being returned.
+		heap.sl = s[:end+1]
+		root := s[0]
+		i := pushRootHoleDownToLeaf(heap, cmp)
+		if i != end {
+			heap.sl = s[:end]
+			s[i] = s[end]
+			bubble(heap, i, cmp)
+		}
+		s[end] = root
+	}
+}
+
+func partialSort[T any, MOM MinOrMax](s []T, k int, cmp func(a, b T) int) {
+	if k >= len(s) {
+		heapSort[T, MOM](s, cmp)
+		return
+	}
+	if k <= 0 {
+		return
+	}
+
+	// Keep the k best elements seen so far in a heap with the opposite ordering,
+	// so that its root is the worst of them.
+	var heap Heap[T, oppositeOf[MOM]]
+	hcmp := func(i, j int) int { return cmp(heap.sl[i], heap.sl[j]) }
+	selectInto(&heap, s[:k], s[k:], cmp, hcmp)
+	sortHeap(&heap, s[:k], hcmp)
+	heap.sl = nil
+}
+
+func nthElement[T any, MOM MinOrMax](s []T, n int, cmp func(a, b T) int) {
+	if n < 0 || n >= len(s) {
+		panic(fmt.Sprintf("heap: NthElement index %v out of range [0:%v]", n, len(s)))
+	}
+
+	if n < len(s)/2 {
+		// Find the n+1 best elements. The worst of them is the nth element.
+		var heap Heap[T, oppositeOf[MOM]]
+		hcmp := func(i, j int) int { return cmp(heap.sl[i], heap.sl[j]) }
+		selectInto(&heap, s[:n+1], s[n+1:], cmp, hcmp)
+		s[0], s[n] = s[n], s[0]
+		heap.sl = nil
+	} else {
+		// Find the len(s)-n worst elements. The best of them is the nth element,
+		// and it's already at s[n] as the root of the heap.
+		var heap Heap[T, MOM]
+		hcmp := func(i, j int) int { return cmp(heap.sl[i], heap.sl[j]) }
+		selectInto(&heap, s[n:], s[:n], cmp, hcmp)
+		heap.sl = nil
+	}
+}
+
+// selectInto makes a heap of keep, then swaps each element of rest that is
+// ordered after the root of the heap (in the heap's MOM ordering) with the
+// root, so that keep ends up holding the len(keep) elements of keep and rest
+// that are ordered last. Callers that want the elements ordered first in some
+// ordering pass a heap with the opposite ordering.
+func selectInto[T any, MOM MinOrMax](heap *Heap[T, MOM], keep, rest []T, cmp func(a, b T) int, hcmp func(i, j int) int) {
+	var mom MOM
+
+	fromSlice(heap, keep, hcmp)
+	for j := range rest {
+		// The root is the first of the kept elements in the heap's ordering, so
+		// it's the one to drop when a later element comes along.
+		if mom.mul()*cmp(rest[j], heap.sl[0]) > 0 {
+			rest[j], _ = replace(heap, rest[j], hcmp)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func randomInts(src rand.Source, n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = int(src.Int63() % 50)
+	}
+	return s
+}
+
+func TestSort(t *testing.T) {
+	src := rand.NewSource(123)
+	for n := range 100 {
+		s := randomInts(src, n)
+		expected := slices.Clone(s)
+		slices.Sort(expected)
+		Sort[int, Min](s)
+		if !slices.Equal(s, expected) {
+			t.Fatalf("Expected %v, got %v\n", expected, s)
+		}
+		slices.Reverse(expected)
+		Sort[int, Max](s)
+		if !slices.Equal(s, expected) {
+			t.Fatalf("Expected %v, got %v\n", expected, s)
+		}
+	}
+}
+
+func TestSortOrderable(t *testing.T) {
+	s := []myCustomType{{3, "c"}, {1, "a"}, {4, "d"}, {1, "a"}, {5, "e"}, {9, "i"}, {2, "b"}, {6, "f"}}
+	SortOrderable[myCustomType, Max](s)
+	var keys []int
+	for _, v := range s {
+		keys = append(keys, v.Key)
+	}
+	if expected := []int{9, 6, 5, 4, 3, 2, 1, 1}; !slices.Equal(keys, expected) {
+		t.Errorf("Expected %v, got %v\n", expected, keys)
+	}
+}
+
+func TestPartialSort(t *testing.T) {
+	src := rand.NewSource(123)
+	for _, n := range []int{0, 1, 2, 10, 100} {
+		for _, k := range []int{-1, 0, 1, 3, n / 2, n - 1, n, n + 5} {
+			s := randomInts(src, n)
+			sorted := slices.Clone(s)
+			slices.Sort(sorted)
+			PartialSort[int, Min](s, k)
+			m := min(max(k, 0), n)
+			if !slices.Equal(s[:m], sorted[:m]) {
+				t.Fatalf("n=%v k=%v: expected prefix %v, got %v\n", n, k, sorted[:m], s[:m])
+			}
+			slices.Sort(s)
+			if !slices.Equal(s, sorted) {
+				t.Fatalf("n=%v k=%v: PartialSort did not permute the slice\n", n, k)
+			}
+		}
+	}
+
+	s := []myCustomType{{3, "c"}, {1, "a"}, {4, "d"}, {5, "e"}, {9, "i"}, {2, "b"}}
+	PartialSortOrderable[myCustomType, Max](s, 2)
+	if s[0].Key != 9 || s[1].Key != 5 {
+		t.Errorf("Expected keys 9 and 5 first, got %v\n", s)
+	}
+}
+
+func TestNthElement(t *testing.T) {
+	src := rand.NewSource(123)
+	for _, size := range []int{1, 2, 7, 100} {
+		for n := range size {
+			s := randomInts(src, size)
+			sorted := slices.Clone(s)
+			slices.Sort(sorted)
+			slices.Reverse(sorted)
+			NthElement[int, Max](s, n)
+			if s[n] != sorted[n] {
+				t.Fatalf("size=%v n=%v: expected %v, got %v\n", size, n, sorted[n], s[n])
+			}
+			for _, v := range s[:n] {
+				if v < s[n] {
+					t.Fatalf("size=%v n=%v: %v before %v\n", size, n, v, s[n])
+				}
+			}
+			for _, v := range s[n+1:] {
+				if v > s[n] {
+					t.Fatalf("size=%v n=%v: %v after %v\n", size, n, v, s[n])
+				}
+			}
+		}
+	}
+
+	s := []myCustomType{{3, "c"}, {1, "a"}, {4, "d"}, {5, "e"}, {9, "i"}, {2, "b"}}
+	NthElementOrderable[myCustomType, Min](s, 1)
+	if s[1].Key != 2 {
+		t.Errorf("Expected key 2 at index 1, got %v\n", s)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected NthElement to panic for an out of range index")
+		}
+	}()
+	NthElement[int, Min]([]int{1, 2}, 2)
+}
+
+func TestSortDoesNotAllocate(t *testing.T) {
+	s := randomInts(rand.NewSource(123), 1000)
+	allocs := testing.AllocsPerRun(10, func() {
+		Sort[int, Min](s)
+		PartialSort[int, Max](s, 10)
+		NthElement[int, Min](s, 100)
+		NthElement[int, Min](s, 900)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %v\n", allocs)
+	}
+}
+
+func BenchmarkSort(b *testing.B) {
+	src := rand.NewSource(123)
+	s := make([]int, 10000)
+	for i := range s {
+		s[i] = int(src.Int63())
+	}
+	b.Run("Sort", func(b *testing.B) {
+		for range b.N {
+			t := slices.Clone(s)
+			Sort[int, Min](t)
+		}
+	})
+	b.Run("slices.Sort", func(b *testing.B) {
+		for range b.N {
+			t := slices.Clone(s)
+			slices.Sort(t)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package heap
+
+import (
+	c "golang.org/x/exp/constraints"
+)
+
+// StableHeap is a min or max heap in which elements that compare equal are
+// popped in the order in which they were pushed (i.e. FIFO order within each
+// priority level). Ties are broken by a sequence number that is assigned to
+// each element as it's added to the heap. The default value of StableHeap is a
+// valid empty heap.
+type StableHeap[T any, MOM MinOrMax] struct {
+	heap Heap[stableEntry[T], MOM]
+	seq  uint64
+}
+
+type stableEntry[T any] struct {
+	val T
+	seq uint64
+}
+
+func stableCmp[T any, MOM MinOrMax](heap *StableHeap[T, MOM], cmp func(a, b T) int) func(i, j int) int {
+	var mom MOM
+	return func(i, j int) int {
+		a, b := &heap.heap.sl[i], &heap.heap.sl[j]
+		if r := cmp(a.val, b.val); r != 0 {
+			return r
+		}
+		// The result is multiplied by mom.mul() to give the heap ordering, so
+		// multiply here too in order to prefer the lower sequence number for both
+		// min and max heaps.
+		return mom.mul() * cmpOrdered(a.seq, b.seq)
+	}
+}
+
+// LenStable returns the number of elements in the heap.
+func LenStable[T any, MOM MinOrMax](heap *StableHeap[T, MOM]) int {
+	return len(heap.heap.sl)
+}
+
+// PushStable adds an element to the heap for a T that satisfies
+// constraints.Ordered.
+func PushStable[T c.Ordered, MOM MinOrMax](heap *StableHeap[T, MOM], elem T) {
+	pushStable(heap, elem, cmpOrdered[T])
+}
+
+// PushStableOrderable adds an element to the heap for a T that implements
+// Orderable.
+func PushStableOrderable[T Orderable[T], MOM MinOrMax](heap *StableHeap[T, MOM], elem T) {
+	pushStable(heap, elem, T.Cmp)
+}
+
+func pushStable[T any, MOM MinOrMax](heap *StableHeap[T, MOM], elem T, cmp func(a, b T) int) {
+	push(&heap.heap, stableEntry[T]{elem, heap.seq}, stableCmp(heap, cmp))
+	heap.seq++
+}
+
+// PopStable removes the min/max element from the heap for a T that satisfies
+// constraints.Ordered. Of the elements that compare equal to the min/max
+// element, the one that was added to the heap first is removed.
+func PopStable[T c.Ordered, MOM MinOrMax](heap *StableHeap[T, MOM]) (T, bool) {
+	return popStable(heap, cmpOrdered[T])
+}
+
+// As for PopStable, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func PopStableOrderable[T Orderable[T], MOM MinOrMax](heap *StableHeap[T, MOM]) (T, bool) {
+	return popStable(heap, T.Cmp)
+}
+
+func popStable[T any, MOM MinOrMax](heap *StableHeap[T, MOM], cmp func(a, b T) int) (T, bool) {
+	e, ok := pop(&heap.heap, stableCmp(heap, cmp))
+	return e.val, ok
+}
+
+// PeekStable returns the element that would be removed by PopStable without
+// removing it.
+func PeekStable[T any, MOM MinOrMax](heap *StableHeap[T, MOM]) (T, bool) {
+	e, ok := Peek(&heap.heap)
+	return e.val, ok
+}
+
+// ClearStable empties the heap.
+func ClearStable[T any, MOM MinOrMax](heap *StableHeap[T, MOM]) {
+	Clear(&heap.heap)
+	heap.seq = 0
+}
+
+// FilterStable is the equivalent of Filter for a StableHeap. The elements that
+// are kept retain their original insertion order.
+func FilterStable[T c.Ordered, MOM MinOrMax](heap *StableHeap[T, MOM], f func(*T) (keepElement bool, breakOrContinue BreakOrContinue)) {
+	filterStable(heap, f, cmpOrdered[T])
+}
+
+// As for FilterStable, but for the case where T cannot be compared using < and
+// there is an implementation of Orderable[T].
+func FilterStableOrderable[T Orderable[T], MOM MinOrMax](heap *StableHeap[T, MOM], f func(*T) (keepElement bool, breakOrContinue BreakOrContinue)) {
+	filterStable(heap, f, T.Cmp)
+}
+
+func filterStable[T any, MOM MinOrMax](heap *StableHeap[T, MOM], f func(*T) (bool, BreakOrContinue), cmp func(a, b T) int) {
+	filter(&heap.heap, func(e *stableEntry[T]) (bool, BreakOrContinue) {
+		return f(&e.val)
+	}, stableCmp(heap, cmp))
+}
+
+// FromSliceStable is the equivalent of FromSlice for a StableHeap. Elements
+// that compare equal are popped in the order in which they appear in the
+// slice. Unlike FromSlice, the slice is not 'moved' into the heap and may be
+// reused following a call to this function.
+func FromSliceStable[T c.Ordered, MOM MinOrMax](heap *StableHeap[T, MOM], slice []T) {
+	fromSliceStable(heap, slice, cmpOrdered[T])
+}
+
+// As for FromSliceStable, but for the case where T cannot be compared using <
+// and there is an implementation of Orderable[T].
+func FromSliceStableOrderable[T Orderable[T], MOM MinOrMax](heap *StableHeap[T, MOM], slice []T) {
+	fromSliceStable(heap, slice, T.Cmp)
+}
+
+func fromSliceStable[T any, MOM MinOrMax](heap *StableHeap[T, MOM], slice []T, cmp func(a, b T) int) {
+	var entries []stableEntry[T]
+	if len(slice) > 0 {
+		entries = make([]stableEntry[T], len(slice))
+		for i, elem := range slice {
+			entries[i] = stableEntry[T]{elem, uint64(i)}
+		}
+	}
+	heap.seq = uint64(len(slice))
+	fromSlice(&heap.heap, entries, stableCmp(heap, cmp))
+}
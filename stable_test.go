@@ -0,0 +1,166 @@
+package heap
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// The Content fields of myCustomType are not considered by Cmp, so we can use
+// them to check the order in which elements with the same Key are popped.
+
+func popAllStable(heap *StableHeap[myCustomType, Max]) []string {
+	var res []string
+	for {
+		v, ok := PopStableOrderable(heap)
+		if !ok {
+			return res
+		}
+		res = append(res, v.Content)
+	}
+}
+
+func TestStablePushAndPop(t *testing.T) {
+	var heap StableHeap[myCustomType, Max]
+	for i := 0; i < 30; i++ {
+		PushStableOrderable(&heap, myCustomType{Key: i % 3, Content: fmt.Sprintf("%v", i)})
+	}
+	var expected []string
+	for k := 2; k >= 0; k-- {
+		for i := k; i < 30; i += 3 {
+			expected = append(expected, fmt.Sprintf("%v", i))
+		}
+	}
+	if res := popAllStable(&heap); !slices.Equal(res, expected) {
+		t.Errorf("Unexpected pop order: %v\n", res)
+	}
+}
+
+func TestStableMinHeap(t *testing.T) {
+	var heap StableHeap[myCustomType, Min]
+	for i := 0; i < 30; i++ {
+		PushStableOrderable(&heap, myCustomType{Key: i % 3, Content: fmt.Sprintf("%v", i)})
+	}
+	for k := 0; k < 3; k++ {
+		for i := k; i < 30; i += 3 {
+			if v, _ := PeekStable(&heap); v.Content != fmt.Sprintf("%v", i) {
+				t.Errorf("Expected to peek %v, got %v\n", i, v.Content)
+			}
+			if v, _ := PopStableOrderable(&heap); v.Content != fmt.Sprintf("%v", i) {
+				t.Errorf("Expected to pop %v, got %v\n", i, v.Content)
+			}
+		}
+	}
+}
+
+func TestStableInterleavedPushAndPop(t *testing.T) {
+	var heap StableHeap[myCustomType, Max]
+	PushStableOrderable(&heap, myCustomType{Key: 1, Content: "a"})
+	PushStableOrderable(&heap, myCustomType{Key: 1, Content: "b"})
+	PushStableOrderable(&heap, myCustomType{Key: 1, Content: "c"})
+	if v, _ := PopStableOrderable(&heap); v.Content != "a" {
+		t.Errorf("Expected to pop a, got %v\n", v.Content)
+	}
+	PushStableOrderable(&heap, myCustomType{Key: 1, Content: "d"})
+	PushStableOrderable(&heap, myCustomType{Key: 2, Content: "e"})
+	if res := popAllStable(&heap); !slices.Equal(res, []string{"e", "b", "c", "d"}) {
+		t.Errorf("Unexpected pop order: %v\n", res)
+	}
+}
+
+func TestStableFilter(t *testing.T) {
+	var heap StableHeap[myCustomType, Max]
+	for i := 0; i < 20; i++ {
+		PushStableOrderable(&heap, myCustomType{Key: i % 2, Content: fmt.Sprintf("%v", i)})
+	}
+	FilterStableOrderable(&heap, func(v *myCustomType) (bool, BreakOrContinue) {
+		var n int
+		fmt.Sscan(v.Content, &n)
+		return n%3 != 0, Continue
+	})
+	expected := []string{"1", "5", "7", "11", "13", "17", "19", "2", "4", "8", "10", "14", "16"}
+	if res := popAllStable(&heap); !slices.Equal(res, expected) {
+		t.Errorf("Unexpected pop order: %v\n", res)
+	}
+}
+
+func TestStableFromSlice(t *testing.T) {
+	var slice []myCustomType
+	for i := 0; i < 30; i++ {
+		slice = append(slice, myCustomType{Key: (i * 7) % 4, Content: fmt.Sprintf("%v", i)})
+	}
+	var heap StableHeap[myCustomType, Max]
+	FromSliceStableOrderable(&heap, slice)
+
+	// FIFO order must continue to hold for elements pushed after FromSlice
+	PushStableOrderable(&heap, myCustomType{Key: 3, Content: "new"})
+
+	var expected []string
+	for k := 3; k >= 0; k-- {
+		for i, v := range slice {
+			if v.Key == k {
+				expected = append(expected, fmt.Sprintf("%v", i))
+			}
+		}
+		if k == 3 {
+			expected = append(expected, "new")
+		}
+	}
+	if res := popAllStable(&heap); !slices.Equal(res, expected) {
+		t.Errorf("Unexpected pop order: %v\n", res)
+	}
+}
+
+func TestStableOrdered(t *testing.T) {
+	var heap StableHeap[int, Min]
+	FromSliceStable(&heap, []int{5, 3, 8, 1})
+	PushStable(&heap, 2)
+	FilterStable(&heap, func(v *int) (bool, BreakOrContinue) {
+		return *v != 8, Continue
+	})
+	var res []int
+	for {
+		v, ok := PopStable(&heap)
+		if !ok {
+			break
+		}
+		res = append(res, v)
+	}
+	if !slices.Equal(res, []int{1, 2, 3, 5}) {
+		t.Errorf("Unexpected pop order: %v\n", res)
+	}
+	if LenStable(&heap) != 0 {
+		t.Errorf("Expected empty heap")
+	}
+}
+
+// Fuzz tests that elements with equal keys come out in FIFO order under a
+// random sequence of pushes, pops and filters.
+func TestStableHeapFuzz(t *testing.T) {
+	src := rand.NewSource(123)
+
+	var heap StableHeap[myCustomType, Min]
+	lastPopped := map[int]int{}
+	for i := 0; i < 10000; i++ {
+		rnd := src.Int63()
+		if rnd%3 == 0 {
+			v, ok := PopStableOrderable(&heap)
+			if !ok {
+				continue
+			}
+			var n int
+			fmt.Sscan(v.Content, &n)
+			if last, ok := lastPopped[v.Key]; ok && last > n {
+				t.Fatalf("Element %v with key %v popped after element %v\n", n, v.Key, last)
+			}
+			lastPopped[v.Key] = n
+		} else if rnd%37 == 0 {
+			FilterStableOrderable(&heap, func(v *myCustomType) (bool, BreakOrContinue) {
+				return len(v.Content)%2 == 0, Continue
+			})
+		} else {
+			PushStableOrderable(&heap, myCustomType{Key: int(rnd % 5), Content: fmt.Sprintf("%v", i)})
+		}
+	}
+}
@@ -1,96 +1,12 @@
 package heap
 
 import (
-	"fmt"
 	"sort"
-	"strings"
 )
 
 // Pretty prints the heap as a tree. Used in tests.
 func debugPrintHeap[T any, MOM MinOrMax](heap *Heap[T, MOM]) string {
-	a := heap.sl
-	var sb strings.Builder
-
-	if len(a) == 0 {
-		return ""
-	}
-
-	bhl := 1
-	for bhl < len(a) {
-		bhl *= 2
-	}
-
-	formatted := make([]string, bhl)
-
-	maxLen := 0
-	for i, h := range a {
-		formatted[i] = fmt.Sprintf("%v", h)
-		if len(formatted[i]) > maxLen {
-			maxLen = len(formatted[i])
-		}
-	}
-	maxLen += 2
-
-	offsets := make([]int, bhl)
-	var f func(i int)
-	f = func(i int) {
-		if i >= bhl {
-			return
-		}
-
-		li := leftChildIndex(i)
-		ri := rightChildIndex(i)
-
-		if ri >= bhl {
-			if i*2 >= bhl {
-				offsets[i] = maxLen + offsets[i-1]
-			} else {
-				offsets[i] = maxLen / 2
-			}
-		} else {
-			f(li)
-			f(ri)
-			offsets[i] = (offsets[li] + offsets[ri]) / 2
-		}
-	}
-
-	f(0)
-
-	startingOffset := (maxLen - len(formatted[bhl/2])) / 2
-	level := 0
-	off := 0
-	for {
-		currentOff := 0
-		wspace := func() {
-			if currentOff >= startingOffset {
-				sb.WriteByte(' ')
-			}
-			currentOff++
-		}
-
-		for i := off; i < off+(1<<level); i++ {
-			if i >= bhl {
-				return sb.String()
-			}
-
-			for currentOff+maxLen/2 < offsets[i] {
-				wspace()
-			}
-			lpad := (maxLen - len(formatted[i])) / 2
-			for j := 0; j < lpad; j++ {
-				wspace()
-			}
-			sb.WriteString(formatted[i])
-			currentOff += len(formatted[i])
-			for j := 0; i+1 < off+(1<<level) && j < maxLen-len(formatted[i])-lpad; j++ {
-				wspace()
-			}
-		}
-		sb.WriteByte('\n')
-
-		off += (1 << level)
-		level++
-	}
+	return Render(heap, RenderOptions[T]{})
 }
 
 // Push an element onto a slice then sort the slice in ascending order
@@ -135,6 +51,16 @@ func naiveHeapFilter(heap *[]int, f func(*int) (keepElement bool, breakOrContinu
 	*heap = newHeap
 }
 
+// Remove the first occurrence of v from the slice.
+func naiveHeapRemove(heap *[]int, v int) {
+	for i, e := range *heap {
+		if e == v {
+			*heap = append((*heap)[:i], (*heap)[i+1:]...)
+			return
+		}
+	}
+}
+
 func slicesHaveSameElems(sl1 []int, sl2 []int) bool {
 	counts1 := make(map[int]int)
 	counts2 := make(map[int]int)
@@ -180,3 +106,90 @@ func checkMaxHeapProperty(heap *Heap[int, Max], i int) bool {
 	}
 	return checkMaxHeapProperty(heap, lci) && checkMaxHeapProperty(heap, rci)
 }
+
+func checkIndexedHeapProperty(heap *IndexedHeap[int, Min]) bool {
+	for i, e := range heap.sl {
+		if e.index != i {
+			return false
+		}
+		if i > 0 && heap.sl[parentIndex(i)].val > e.val {
+			return false
+		}
+	}
+	return true
+}
+
+func checkDaryMinHeapProperty[A Arity](heap *DaryHeap[int, Min, A]) bool {
+	var a A
+	for i := 1; i < len(heap.sl); i++ {
+		if heap.sl[daryParentIndex(i, a.arity())] > heap.sl[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func checkMinMaxHeapProperty(heap *MinMaxHeap[int]) bool {
+	for i := 1; i < len(heap.sl); i++ {
+		pi := parentIndex(i)
+		if levelDirMinMax(pi)*cmpOrdered(heap.sl[i], heap.sl[pi]) < 0 {
+			return false
+		}
+		if pi > 0 {
+			gpi := parentIndex(pi)
+			if levelDirMinMax(gpi)*cmpOrdered(heap.sl[i], heap.sl[gpi]) < 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Remove the last element from the slice, which is the max element if the
+// slice is sorted in ascending order.
+func naiveHeapPopMax(heap *[]int) (v int, ok bool) {
+	if len(*heap) == 0 {
+		return
+	}
+	ok = true
+	v = (*heap)[len(*heap)-1]
+	*heap = (*heap)[:len(*heap)-1]
+	return
+}
+
+// Checks the structure of a min FibHeap: every child is no less than its
+// parent, parent pointers and degrees are consistent, the root is a minimal
+// root and the number of nodes matches the heap's length.
+func checkFibHeapProperty(heap *FibHeap[int, Min]) bool {
+	if heap.root == nil {
+		return heap.n == 0
+	}
+	count := 0
+	var checkList func(first, parent *fibNode[int]) bool
+	checkList = func(first, parent *fibNode[int]) bool {
+		n := 0
+		x := first
+		for {
+			count++
+			n++
+			if x.parent != parent || x.right.left != x || x.removed {
+				return false
+			}
+			if parent != nil && x.val < parent.val {
+				return false
+			}
+			if parent == nil && x.val < heap.root.val {
+				return false
+			}
+			if x.child != nil && !checkList(x.child, x) {
+				return false
+			}
+			x = x.right
+			if x == first {
+				break
+			}
+		}
+		return parent == nil || n == parent.degree
+	}
+	return checkList(heap.root, nil) && count == heap.n
+}
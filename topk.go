@@ -0,0 +1,98 @@
+package heap
+
+import (
+	"slices"
+
+	c "golang.org/x/exp/constraints"
+)
+
+// TopK is a bounded heap that keeps the k best elements offered to it, where
+// the best elements are the largest for TopK[T, Max] and the smallest for
+// TopK[T, Min]. Once k elements have been offered, each subsequent offer
+// discards the worst of the kept elements and the offered element. Offer is
+// O(log k) and the heap never holds more than k elements.
+//
+// Unlike Heap, the default value of TopK is not usable. Use NewTopK or
+// NewTopKOrderable to create a TopK.
+type TopK[T any, MOM MinOrMax] struct {
+	// The root of heap is the worst kept element, so heap is ordered in the
+	// opposite direction to MOM.
+	heap Heap[T, Min]
+	k    int
+	cmp  func(a, b T) int
+}
+
+// NewTopK returns an empty TopK that keeps the k best elements for a T that
+// satisfies constraints.Ordered. Panics if k is negative.
+func NewTopK[T c.Ordered, MOM MinOrMax](k int) *TopK[T, MOM] {
+	return newTopK[T, MOM](k, cmpOrdered[T])
+}
+
+// NewTopKOrderable returns an empty TopK that keeps the k best elements for a
+// T that implements Orderable. Panics if k is negative.
+func NewTopKOrderable[T Orderable[T], MOM MinOrMax](k int) *TopK[T, MOM] {
+	return newTopK[T, MOM](k, func(a, b T) int { return a.Cmp(b) })
+}
+
+func newTopK[T any, MOM MinOrMax](k int, cmp func(a, b T) int) *TopK[T, MOM] {
+	if k < 0 {
+		panic("heap: negative TopK capacity")
+	}
+	return &TopK[T, MOM]{k: k, cmp: cmp}
+}
+
+func (t *TopK[T, MOM]) cmpIndices(i, j int) int {
+	var mom MOM
+	return -mom.mul() * t.cmp(t.heap.sl[i], t.heap.sl[j])
+}
+
+// Len returns the number of elements currently kept.
+func (t *TopK[T, MOM]) Len() int {
+	return len(t.heap.sl)
+}
+
+// Cap returns k, the maximum number of elements kept.
+func (t *TopK[T, MOM]) Cap() int {
+	return t.k
+}
+
+// Offer adds elem to the kept elements. If k elements were already kept, the
+// worst of those elements and elem is discarded and returned with
+// didEvict == true. If elem compares equal to the worst kept element then elem
+// is the one discarded.
+func (t *TopK[T, MOM]) Offer(elem T) (evicted T, didEvict bool) {
+	if len(t.heap.sl) < t.k {
+		push(&t.heap, elem, t.cmpIndices)
+		return
+	}
+	if t.k == 0 {
+		return elem, true
+	}
+
+	var mom MOM
+	if mom.mul()*t.cmp(elem, t.heap.sl[0]) >= 0 {
+		return elem, true
+	}
+	return replace(&t.heap, elem, t.cmpIndices)
+}
+
+// Worst returns the worst kept element, which is the next element to be
+// evicted. The second return value is false if no elements are kept.
+func (t *TopK[T, MOM]) Worst() (T, bool) {
+	return Peek(&t.heap)
+}
+
+// Results returns a newly allocated slice of the kept elements sorted from best
+// to worst (i.e. descending for Max and ascending for Min). The kept elements
+// are not modified.
+func (t *TopK[T, MOM]) Results() []T {
+	var mom MOM
+	res := slices.Clone(t.heap.sl)
+	slices.SortFunc(res, func(a, b T) int { return mom.mul() * t.cmp(a, b) })
+	return res
+}
+
+// Reset discards all kept elements.
+func (t *TopK[T, MOM]) Reset() {
+	Clear(&t.heap)
+}
@@ -0,0 +1,94 @@
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestTopKLargest(t *testing.T) {
+	topk := NewTopK[int, Max](3)
+	for _, elem := range []int{5, 1, 9, 3, 7, 2, 8} {
+		topk.Offer(elem)
+	}
+	if res := topk.Results(); !slices.Equal(res, []int{9, 8, 7}) {
+		t.Errorf("Unexpected results: %v\n", res)
+	}
+	if topk.Len() != 3 || topk.Cap() != 3 {
+		t.Errorf("Unexpected length/capacity: %v/%v\n", topk.Len(), topk.Cap())
+	}
+	if w, ok := topk.Worst(); !ok || w != 7 {
+		t.Errorf("Expected worst (7,true), got (%v,%v)\n", w, ok)
+	}
+}
+
+func TestTopKSmallest(t *testing.T) {
+	topk := NewTopK[int, Min](3)
+	for _, elem := range []int{5, 1, 9, 3, 7, 2, 8} {
+		topk.Offer(elem)
+	}
+	if res := topk.Results(); !slices.Equal(res, []int{1, 2, 3}) {
+		t.Errorf("Unexpected results: %v\n", res)
+	}
+}
+
+func TestTopKOfferEvicts(t *testing.T) {
+	topk := NewTopK[int, Max](2)
+	if _, ev := topk.Offer(5); ev {
+		t.Errorf("Expected no eviction while below capacity")
+	}
+	if _, ev := topk.Offer(3); ev {
+		t.Errorf("Expected no eviction while below capacity")
+	}
+	if v, ev := topk.Offer(4); !ev || v != 3 {
+		t.Errorf("Expected (3,true), got (%v,%v)\n", v, ev)
+	}
+	if v, ev := topk.Offer(1); !ev || v != 1 {
+		t.Errorf("Expected offered element to be evicted, got (%v,%v)\n", v, ev)
+	}
+	if v, ev := topk.Offer(4); !ev || v != 4 {
+		t.Errorf("Expected tied element to be evicted, got (%v,%v)\n", v, ev)
+	}
+}
+
+func TestTopKZero(t *testing.T) {
+	topk := NewTopK[int, Max](0)
+	if v, ev := topk.Offer(1); !ev || v != 1 {
+		t.Errorf("Expected (1,true), got (%v,%v)\n", v, ev)
+	}
+	if len(topk.Results()) != 0 {
+		t.Errorf("Expected no results")
+	}
+}
+
+func TestTopKOrderable(t *testing.T) {
+	topk := NewTopKOrderable[myCustomType, Max](2)
+	topk.Offer(myCustomType{Key: 1, Content: "a"})
+	topk.Offer(myCustomType{Key: 3, Content: "c"})
+	topk.Offer(myCustomType{Key: 2, Content: "b"})
+	res := topk.Results()
+	if len(res) != 2 || res[0].Content != "c" || res[1].Content != "b" {
+		t.Errorf("Unexpected results: %v\n", res)
+	}
+	topk.Reset()
+	if topk.Len() != 0 {
+		t.Errorf("Expected empty TopK after Reset")
+	}
+}
+
+func TestTopKFuzz(t *testing.T) {
+	src := rand.NewSource(123)
+	elems := make([]int, 10000)
+	for i := range elems {
+		elems[i] = int(src.Int63() % 1000)
+	}
+	topk := NewTopK[int, Max](100)
+	for _, elem := range elems {
+		topk.Offer(elem)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(elems)))
+	if res := topk.Results(); !slices.Equal(res, elems[:100]) {
+		t.Errorf("Unexpected results: %v\n", res)
+	}
+}
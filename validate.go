@@ -0,0 +1,47 @@
+package heap
+
+import (
+	"errors"
+	"fmt"
+
+	c "golang.org/x/exp/constraints"
+)
+
+// ErrInvalidHeap is returned (wrapped) by Validate and ValidateOrderable if the
+// heap property does not hold.
+var ErrInvalidHeap = errors.New("heap: heap property violated")
+
+// Validate checks that the heap property holds for every element of the heap,
+// for a T that satisfies constraints.Ordered. It returns nil if the heap is
+// valid, or else an error wrapping ErrInvalidHeap that names the first child
+// (in slice order) that is ordered before its parent, along with the parent.
+//
+// The heap property can be violated if the ordering of elements is changed
+// without going through the functions of this package (e.g. if T is a pointer
+// type and the values it points to are modified), or if Cmp is inconsistent.
+// Validate is mainly of use in tests and debug builds.
+func Validate[T c.Ordered, MOM MinOrMax](heap *Heap[T, MOM]) error {
+	return validate(heap, cmpOrdered[T])
+}
+
+// ValidateOrderable is the equivalent of Validate for a T that implements
+// Orderable.
+func ValidateOrderable[T Orderable[T], MOM MinOrMax](heap *Heap[T, MOM]) error {
+	return validate(heap, T.Cmp)
+}
+
+func validate[T any, MOM MinOrMax](heap *Heap[T, MOM], cmp func(a, b T) int) error {
+	var mom MOM
+
+	for i := 1; i < len(heap.sl); i++ {
+		p := parentIndex(i)
+		if mom.mul()*cmp(heap.sl[i], heap.sl[p]) < 0 {
+			rel := "less"
+			if mom.mul() < 0 {
+				rel = "greater"
+			}
+			return fmt.Errorf("%w: child at index %v (%v) is %v than its parent at index %v (%v)", ErrInvalidHeap, i, heap.sl[i], rel, p, heap.sl[p])
+		}
+	}
+	return nil
+}
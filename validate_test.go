@@ -0,0 +1,60 @@
+package heap
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	src := rand.NewSource(123)
+	var minHeap Heap[int, Min]
+	var maxHeap Heap[int, Max]
+	if err := Validate(&minHeap); err != nil {
+		t.Errorf("Unexpected error for empty heap: %v\n", err)
+	}
+	for range 500 {
+		v := int(src.Int63() % 100)
+		Push(&minHeap, v)
+		Push(&maxHeap, v)
+		if err := Validate(&minHeap); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if err := Validate(&maxHeap); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+	}
+}
+
+func TestValidateReportsFirstViolation(t *testing.T) {
+	var heap Heap[int, Min]
+	FromSlice(&heap, []int{1, 2, 3, 4, 5, 6, 7})
+	heap.sl[4] = 0
+	heap.sl[6] = 0
+	err := Validate(&heap)
+	if !errors.Is(err, ErrInvalidHeap) {
+		t.Fatalf("Expected ErrInvalidHeap, got %v\n", err)
+	}
+	expected := "heap: heap property violated: child at index 4 (0) is less than its parent at index 1 (2)"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q\n", expected, err.Error())
+	}
+}
+
+func TestValidateOrderableMax(t *testing.T) {
+	var heap Heap[myCustomType, Max]
+	for _, k := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		PushOrderable(&heap, myCustomType{Key: k})
+	}
+	if err := ValidateOrderable(&heap); err != nil {
+		t.Errorf("Unexpected error: %v\n", err)
+	}
+	heap.sl[len(heap.sl)-1].Key = 100
+	err := ValidateOrderable(&heap)
+	if !errors.Is(err, ErrInvalidHeap) {
+		t.Fatalf("Expected ErrInvalidHeap, got %v\n", err)
+	}
+	if got := err.Error(); got != "heap: heap property violated: child at index 7 ({100 }) is greater than its parent at index 3 ({4 })" {
+		t.Errorf("Unexpected error message: %v\n", got)
+	}
+}